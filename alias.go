@@ -0,0 +1,80 @@
+package yetenv
+
+import (
+	"strings"
+	"sync"
+)
+
+var defaultAliases = map[string]Environment{
+	environmentVariableValueProduction: Production,
+	"prod":                             Production,
+	"prd":                              Production,
+	environmentVariableValueStaging:    Staging,
+	"stage":                            Staging,
+	"stg":                              Staging,
+	environmentVariableValueTest:       Test,
+	"testing":                          Test,
+	"develop":                          Develop,
+	"development":                      Develop,
+	"dev":                              Develop,
+	"local":                            Develop,
+}
+
+var (
+	aliasMutex sync.RWMutex
+	aliases    = copyAliases(defaultAliases)
+)
+
+// RegisterAlias maps an additional (case-insensitive) environment variable value to an Environment.
+// An existing alias will be overwritten.
+func RegisterAlias(alias string, environment Environment) {
+	aliasMutex.Lock()
+	defer aliasMutex.Unlock()
+
+	aliases[normalizeVariableValue(alias)] = environment
+}
+
+// UnregisterAlias removes an alias, including the built-in ones.
+func UnregisterAlias(alias string) {
+	aliasMutex.Lock()
+	defer aliasMutex.Unlock()
+
+	delete(aliases, normalizeVariableValue(alias))
+}
+
+// ResetAliases restores the built-in alias table.
+func ResetAliases() {
+	aliasMutex.Lock()
+	defer aliasMutex.Unlock()
+
+	aliases = copyAliases(defaultAliases)
+}
+
+// Aliases returns a copy of the current alias table.
+func Aliases() map[string]Environment {
+	aliasMutex.RLock()
+	defer aliasMutex.RUnlock()
+
+	return copyAliases(aliases)
+}
+
+func environmentFromAlias(variableValue string) (Environment, bool) {
+	aliasMutex.RLock()
+	defer aliasMutex.RUnlock()
+
+	environment, ok := aliases[variableValue]
+	return environment, ok
+}
+
+func normalizeVariableValue(variableValue string) string {
+	return strings.ToLower(strings.TrimSpace(variableValue))
+}
+
+func copyAliases(source map[string]Environment) map[string]Environment {
+	target := make(map[string]Environment, len(source))
+	for alias, environment := range source {
+		target[alias] = environment
+	}
+
+	return target
+}
@@ -0,0 +1,56 @@
+package yetenv
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEnvironment_BuiltInAliases(t *testing.T) {
+	expectations := map[string]Environment{
+		"prod":        Production,
+		"PRD":         Production,
+		"stg":         Staging,
+		"Stage":       Staging,
+		"testing":     Test,
+		"dev":         Develop,
+		"development": Develop,
+		"local":       Develop,
+		" prod ":      Production,
+	}
+
+	for value, expectedEnv := range expectations {
+		value, expectedEnv := value, expectedEnv
+
+		t.Run(fmt.Sprintf("ALIAS_ENV=%s", value), func(t *testing.T) {
+			err := os.Setenv("ALIAS_ENV", value)
+			require.NoError(t, err)
+
+			assert.Equal(t, expectedEnv, GetEnvironmentFromVariable("ALIAS_ENV"))
+		})
+	}
+}
+
+func TestRegisterAlias(t *testing.T) {
+	defer ResetAliases()
+
+	RegisterAlias("LIVE", Production)
+	assert.Equal(t, Production, Aliases()["live"])
+
+	err := os.Setenv("ALIAS_ENV", "live")
+	require.NoError(t, err)
+	assert.Equal(t, Production, GetEnvironmentFromVariable("ALIAS_ENV"))
+
+	UnregisterAlias("live")
+	assert.Equal(t, Develop, GetEnvironmentFromVariable("ALIAS_ENV"))
+
+	UnregisterAlias("prod")
+	_, ok := Aliases()["prod"]
+	assert.False(t, ok)
+
+	ResetAliases()
+	assert.Equal(t, Production, Aliases()["prod"])
+}
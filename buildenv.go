@@ -0,0 +1,42 @@
+package yetenv
+
+const (
+	// PrecedenceVariable lets a known value of the environment variable win over BuildEnvironment.
+	PrecedenceVariable = "variable"
+	// PrecedenceBuild lets BuildEnvironment win over the environment variable.
+	PrecedenceBuild = "build"
+)
+
+// BuildEnvironment defines the fallback environment which is used instead of Develop when the environment variable is
+// empty or unknown. It is meant to be set at build time:
+//
+//	go build -ldflags "-X github.com/pvormste/yetenv.BuildEnvironment=production"
+//
+// The value is treated like a value of the environment variable, so aliases and registered environments can be used.
+var BuildEnvironment = ""
+
+// BuildEnvironmentPrecedence decides between BuildEnvironment and the environment variable.
+// It is either PrecedenceVariable (default) or PrecedenceBuild and can be set at build time as well:
+//
+//	go build -ldflags "-X github.com/pvormste/yetenv.BuildEnvironmentPrecedence=build"
+var BuildEnvironmentPrecedence = PrecedenceVariable
+
+func buildEnvironment() (Environment, bool) {
+	return lookupEnvironment(BuildEnvironment)
+}
+
+func buildEnvironmentHasPrecedence() (Environment, bool) {
+	if normalizeVariableValue(BuildEnvironmentPrecedence) != PrecedenceBuild {
+		return "", false
+	}
+
+	return buildEnvironment()
+}
+
+func fallbackEnvironment() Environment {
+	if environment, ok := buildEnvironment(); ok {
+		return environment
+	}
+
+	return Develop
+}
@@ -0,0 +1,68 @@
+package yetenv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildEnvironment(t *testing.T) {
+	variableName := "BUILD_TEST_ENV"
+
+	defer func() {
+		BuildEnvironment = ""
+		BuildEnvironmentPrecedence = PrecedenceVariable
+		_ = os.Unsetenv(variableName)
+	}()
+
+	BuildEnvironment = "prod"
+
+	t.Run("should use build environment as fallback", func(t *testing.T) {
+		values := []string{"", "unknown"}
+
+		for _, value := range values {
+			require.NoError(t, os.Setenv(variableName, value))
+			assert.Equal(t, Production, GetEnvironmentFromVariable(variableName))
+		}
+	})
+
+	t.Run("should prefer variable with variable precedence", func(t *testing.T) {
+		require.NoError(t, os.Setenv(variableName, "staging"))
+		assert.Equal(t, Staging, GetEnvironmentFromVariable(variableName))
+	})
+
+	t.Run("should prefer build environment with build precedence", func(t *testing.T) {
+		BuildEnvironmentPrecedence = PrecedenceBuild
+		defer func() { BuildEnvironmentPrecedence = PrecedenceVariable }()
+
+		require.NoError(t, os.Setenv(variableName, "staging"))
+		assert.Equal(t, Production, GetEnvironmentFromVariable(variableName))
+
+		environment, ok := VariableDetector{VariableNames: []string{variableName}}.DetectEnvironment()
+		assert.True(t, ok)
+		assert.Equal(t, Production, environment)
+	})
+
+	t.Run("should use build environment for empty variable in strict detection", func(t *testing.T) {
+		require.NoError(t, os.Unsetenv(variableName))
+
+		actualEnv, err := GetEnvironmentFromVariableStrict(variableName)
+		assert.NoError(t, err)
+		assert.Equal(t, Production, actualEnv)
+
+		require.NoError(t, os.Setenv(variableName, "unknown"))
+
+		_, err = GetEnvironmentFromVariableStrict(variableName)
+		assert.Error(t, err)
+	})
+
+	t.Run("should ignore unknown build environment", func(t *testing.T) {
+		BuildEnvironment = "unknown"
+		defer func() { BuildEnvironment = "prod" }()
+
+		require.NoError(t, os.Unsetenv(variableName))
+		assert.Equal(t, Develop, GetEnvironmentFromVariable(variableName))
+	})
+}
@@ -0,0 +1,152 @@
+package yetenv
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"regexp"
+)
+
+// DefaultKubernetesNamespaceFile is the path of the namespace file which is mounted into pods by the service account.
+const DefaultKubernetesNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+var (
+	ErrEnvironmentNotDetected = errors.New("environment could not be detected by the environment detector")
+)
+
+// EnvironmentDetector detects the current Environment. It returns false when it is not able to detect the environment,
+// so the next detector of a DetectorChain can take over.
+type EnvironmentDetector interface {
+	DetectEnvironment() (Environment, bool)
+}
+
+// DetectorChain is an EnvironmentDetector which asks its detectors in order and uses the first detected Environment.
+type DetectorChain []EnvironmentDetector
+
+// DetectEnvironment returns the Environment of the first detector which is able to detect it.
+func (d DetectorChain) DetectEnvironment() (Environment, bool) {
+	for _, detector := range d {
+		if environment, ok := detector.DetectEnvironment(); ok {
+			return environment, true
+		}
+	}
+
+	return "", false
+}
+
+// VariableDetector detects the Environment from the first set OS environment variable of VariableNames.
+// Unknown variable values are not detected. If VariableNames is empty, DefaultVariableName is used.
+// BuildEnvironment is detected instead of the variables when it has precedence (see BuildEnvironmentPrecedence).
+type VariableDetector struct {
+	VariableNames []string
+}
+
+// DetectEnvironment detects the Environment from the OS environment.
+func (d VariableDetector) DetectEnvironment() (Environment, bool) {
+	if environment, ok := buildEnvironmentHasPrecedence(); ok {
+		return environment, true
+	}
+
+	variableNames := d.VariableNames
+	if len(variableNames) == 0 {
+		variableNames = []string{DefaultVariableName}
+	}
+
+	for _, variableName := range variableNames {
+		envRaw := os.Getenv(variableName)
+		if normalizeVariableValue(envRaw) == "" {
+			continue
+		}
+
+		return lookupEnvironment(envRaw)
+	}
+
+	return "", false
+}
+
+// HostnamePattern maps a hostname pattern to an Environment.
+type HostnamePattern struct {
+	Pattern     *regexp.Regexp
+	Environment Environment
+}
+
+// HostnameDetector detects the Environment from the first pattern which matches the hostname.
+// Hostname defaults to os.Hostname.
+type HostnameDetector struct {
+	Patterns []HostnamePattern
+	Hostname func() (string, error)
+}
+
+// DetectEnvironment detects the Environment from the hostname.
+func (d HostnameDetector) DetectEnvironment() (Environment, bool) {
+	hostnameFunc := d.Hostname
+	if hostnameFunc == nil {
+		hostnameFunc = os.Hostname
+	}
+
+	hostname, err := hostnameFunc()
+	if err != nil {
+		return "", false
+	}
+
+	for _, pattern := range d.Patterns {
+		if pattern.Pattern.MatchString(hostname) {
+			return pattern.Environment, true
+		}
+	}
+
+	return "", false
+}
+
+// FileMarkerDetector detects the Environment from the content of a marker file (e.g. '/etc/app-environment').
+// The content is treated like a value of the environment variable.
+type FileMarkerDetector struct {
+	Path string
+}
+
+// DetectEnvironment detects the Environment from the marker file.
+func (d FileMarkerDetector) DetectEnvironment() (Environment, bool) {
+	content, ok := readDetectorFile(d.Path)
+	if !ok {
+		return "", false
+	}
+
+	return lookupEnvironment(content)
+}
+
+// KubernetesNamespaceDetector detects the Environment from the Kubernetes namespace of the service account.
+// The namespace is mapped by Namespaces first and is treated like a value of the environment variable otherwise.
+// NamespaceFile defaults to DefaultKubernetesNamespaceFile.
+type KubernetesNamespaceDetector struct {
+	NamespaceFile string
+	Namespaces    map[string]Environment
+}
+
+// DetectEnvironment detects the Environment from the Kubernetes namespace.
+func (d KubernetesNamespaceDetector) DetectEnvironment() (Environment, bool) {
+	namespaceFile := d.NamespaceFile
+	if namespaceFile == "" {
+		namespaceFile = DefaultKubernetesNamespaceFile
+	}
+
+	namespace, ok := readDetectorFile(namespaceFile)
+	if !ok {
+		return "", false
+	}
+
+	if environment, ok := d.Namespaces[normalizeVariableValue(namespace)]; ok {
+		return environment, true
+	}
+
+	return lookupEnvironment(namespace)
+}
+
+func readDetectorFile(path string) (string, bool) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+
+	value := normalizeVariableValue(string(content))
+	return value, value != ""
+}
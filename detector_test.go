@@ -0,0 +1,166 @@
+package yetenv
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticDetector struct {
+	environment Environment
+	ok          bool
+}
+
+func (d staticDetector) DetectEnvironment() (Environment, bool) {
+	return d.environment, d.ok
+}
+
+func TestDetectorChain_DetectEnvironment(t *testing.T) {
+	t.Run("should return first detected environment", func(t *testing.T) {
+		chain := DetectorChain{
+			staticDetector{ok: false},
+			staticDetector{environment: Staging, ok: true},
+			staticDetector{environment: Production, ok: true},
+		}
+
+		actualEnv, ok := chain.DetectEnvironment()
+		assert.True(t, ok)
+		assert.Equal(t, Staging, actualEnv)
+	})
+
+	t.Run("should return false when no detector detects an environment", func(t *testing.T) {
+		_, ok := DetectorChain{staticDetector{ok: false}}.DetectEnvironment()
+		assert.False(t, ok)
+	})
+}
+
+func TestVariableDetector_DetectEnvironment(t *testing.T) {
+	defer func() {
+		_ = os.Unsetenv("DETECTOR_APP_ENV")
+		_ = os.Unsetenv("DETECTOR_GO_ENV")
+	}()
+
+	detector := VariableDetector{VariableNames: []string{"DETECTOR_APP_ENV", "DETECTOR_GO_ENV"}}
+
+	require.NoError(t, os.Unsetenv("DETECTOR_APP_ENV"))
+	require.NoError(t, os.Setenv("DETECTOR_GO_ENV", "prod"))
+
+	actualEnv, ok := detector.DetectEnvironment()
+	assert.True(t, ok)
+	assert.Equal(t, Production, actualEnv)
+
+	require.NoError(t, os.Setenv("DETECTOR_APP_ENV", "unknown"))
+
+	_, ok = detector.DetectEnvironment()
+	assert.False(t, ok)
+}
+
+func TestHostnameDetector_DetectEnvironment(t *testing.T) {
+	detector := HostnameDetector{
+		Patterns: []HostnamePattern{
+			{Pattern: regexp.MustCompile(`^prod-`), Environment: Production},
+			{Pattern: regexp.MustCompile(`^stg-`), Environment: Staging},
+		},
+		Hostname: func() (string, error) {
+			return "stg-web-1", nil
+		},
+	}
+
+	actualEnv, ok := detector.DetectEnvironment()
+	assert.True(t, ok)
+	assert.Equal(t, Staging, actualEnv)
+
+	detector.Hostname = func() (string, error) {
+		return "", errors.New("no hostname")
+	}
+
+	_, ok = detector.DetectEnvironment()
+	assert.False(t, ok)
+}
+
+func TestFileMarkerDetector_DetectEnvironment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yetenv")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	markerFile := filepath.Join(dir, "app-environment")
+	require.NoError(t, ioutil.WriteFile(markerFile, []byte("staging\n"), 0600))
+
+	actualEnv, ok := FileMarkerDetector{Path: markerFile}.DetectEnvironment()
+	assert.True(t, ok)
+	assert.Equal(t, Staging, actualEnv)
+
+	_, ok = FileMarkerDetector{Path: filepath.Join(dir, "missing")}.DetectEnvironment()
+	assert.False(t, ok)
+}
+
+func TestKubernetesNamespaceDetector_DetectEnvironment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yetenv")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	namespaceFile := filepath.Join(dir, "namespace")
+
+	t.Run("should map namespace by namespaces", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(namespaceFile, []byte("shop-live"), 0600))
+
+		detector := KubernetesNamespaceDetector{
+			NamespaceFile: namespaceFile,
+			Namespaces:    map[string]Environment{"shop-live": Production},
+		}
+
+		actualEnv, ok := detector.DetectEnvironment()
+		assert.True(t, ok)
+		assert.Equal(t, Production, actualEnv)
+	})
+
+	t.Run("should fall back to environment values", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(namespaceFile, []byte("stg"), 0600))
+
+		actualEnv, ok := KubernetesNamespaceDetector{NamespaceFile: namespaceFile}.DetectEnvironment()
+		assert.True(t, ok)
+		assert.Equal(t, Staging, actualEnv)
+	})
+
+	t.Run("should not detect unknown namespaces", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(namespaceFile, []byte("kube-system"), 0600))
+
+		_, ok := KubernetesNamespaceDetector{NamespaceFile: namespaceFile}.DetectEnvironment()
+		assert.False(t, ok)
+	})
+}
+
+func TestConfigLoader_UseEnvironmentDetector(t *testing.T) {
+	t.Run("should use detected environment", func(t *testing.T) {
+		resetEnv()
+
+		c := testConfig{}
+		err := NewConfigLoader().
+			UseLoadPath("./testdata").
+			UseEnvironmentDetector(staticDetector{environment: Staging, ok: true}).
+			UseDefaultLoadBehavior().
+			LoadInto(&c)
+
+		assert.NoError(t, err)
+		assert.True(t, c.Staging)
+	})
+
+	t.Run("should return error when using strict environment detection", func(t *testing.T) {
+		resetEnv()
+
+		c := testConfig{}
+		err := NewConfigLoader().
+			UseEnvironmentDetector(staticDetector{ok: false}).
+			UseStrictEnvironmentDetection().
+			UseDefaultLoadBehavior().
+			LoadInto(&c)
+
+		assert.Equal(t, ErrEnvironmentNotDetected, err)
+	})
+}
@@ -0,0 +1,163 @@
+package yetenv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// RedactedValue is used instead of the value of secret fields when dumping a config.
+const RedactedValue = "******"
+
+// DumpFormat defines the output format of Dump.
+type DumpFormat int
+
+const (
+	DumpText DumpFormat = iota
+	DumpJSON
+	DumpYAML
+)
+
+var (
+	ErrUnknownDumpFormat = errors.New("dump format is unknown - only text, json or yaml is allowed")
+	ErrNoConfigStruct    = errors.New("config must be a struct or a pointer to a struct")
+)
+
+// DumpOptions configures Dump.
+type DumpOptions struct {
+	Format DumpFormat
+	// Provenance adds the source of every field to the output when it is set (e.g. ConfigLoader.Provenance()).
+	Provenance Provenance
+}
+
+// Dump renders a loaded config struct, so it can be printed at startup. The values of fields tagged with
+// `secret:"true"` (or of nested structs tagged with it) are replaced by RedactedValue. Pointers, slices and maps of
+// structs are dumped element by element (e.g. 'Replicas[0].Password'), other values which could contain secret
+// fields (e.g. interfaces) are replaced by RedactedValue as well.
+// Text output contains one 'Path = value' line per field, JSON and YAML output mirror the nested structs.
+func Dump(cfg interface{}, options DumpOptions) (string, error) {
+	fields := collectStructFields(cfg)
+	if fields == nil {
+		return "", ErrNoConfigStruct
+	}
+
+	switch options.Format {
+	case DumpText:
+		return dumpText(fields, options.Provenance), nil
+	case DumpJSON:
+		data, err := json.MarshalIndent(dumpTree(fields, options.Provenance), "", "  ")
+		return string(data), err
+	case DumpYAML:
+		data, err := yaml.Marshal(dumpTree(fields, options.Provenance))
+		return string(data), err
+	}
+
+	return "", ErrUnknownDumpFormat
+}
+
+func dumpText(fields []structField, provenance Provenance) string {
+	var builder strings.Builder
+	for _, field := range dumpedFields(fields) {
+		builder.WriteString(fmt.Sprintf("%s = %v", field.path, dumpValue(field)))
+
+		if source, ok := provenance[field.path]; ok {
+			builder.WriteString(fmt.Sprintf(" (%s)", source))
+		}
+
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+func dumpTree(fields []structField, provenance Provenance) map[string]interface{} {
+	var tree interface{} = map[string]interface{}{}
+
+	for _, field := range dumpedFields(fields) {
+		var value interface{} = dumpValue(field)
+		if provenance != nil {
+			source := "unchanged"
+			if fieldSource, ok := provenance[field.path]; ok {
+				source = fieldSource.String()
+			}
+
+			value = map[string]interface{}{
+				"value":  value,
+				"source": source,
+			}
+		}
+
+		tree = insertDumpValue(tree, field.segments, value)
+	}
+
+	return tree.(map[string]interface{})
+}
+
+// dumpedFields skips slices, arrays and maps whose struct elements are dumped as separate fields.
+func dumpedFields(fields []structField) []structField {
+	dumped := make([]structField, 0, len(fields))
+	for i, field := range fields {
+		if field.composite && i+1 < len(fields) && strings.HasPrefix(fields[i+1].path, field.path+"[") {
+			continue
+		}
+
+		dumped = append(dumped, field)
+	}
+
+	return dumped
+}
+
+func insertDumpValue(node interface{}, segments []pathSegment, value interface{}) interface{} {
+	if len(segments) == 0 {
+		return value
+	}
+
+	segment := segments[0]
+	if segment.index >= 0 {
+		list, _ := node.([]interface{})
+		for len(list) <= segment.index {
+			list = append(list, nil)
+		}
+
+		list[segment.index] = insertDumpValue(list[segment.index], segments[1:], value)
+		return list
+	}
+
+	children, ok := node.(map[string]interface{})
+	if !ok {
+		children = map[string]interface{}{}
+	}
+
+	children[segment.name] = insertDumpValue(children[segment.name], segments[1:], value)
+	return children
+}
+
+// dumpValue fails closed: values which could contain secret fields are redacted as well.
+func dumpValue(field structField) interface{} {
+	if field.secret {
+		return RedactedValue
+	}
+
+	value := field.value
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+
+		value = value.Elem()
+	}
+
+	if field.composite && value.Len() == 0 {
+		return value.Interface()
+	}
+
+	if !isWalkableType(value.Type()) {
+		return RedactedValue
+	}
+
+	return value.Interface()
+}
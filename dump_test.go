@@ -0,0 +1,195 @@
+package yetenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dumpTestConfig struct {
+	Database struct {
+		Host     string
+		Password string `secret:"true"`
+	}
+	Credentials struct {
+		Token string
+	} `secret:"true"`
+	Port int
+}
+
+func newDumpTestConfig() dumpTestConfig {
+	c := dumpTestConfig{Port: 8080}
+	c.Database.Host = "localhost"
+	c.Database.Password = "password"
+	c.Credentials.Token = "token"
+
+	return c
+}
+
+func TestDump(t *testing.T) {
+	t.Run("should dump text with redacted secrets", func(t *testing.T) {
+		c := newDumpTestConfig()
+
+		dump, err := Dump(&c, DumpOptions{Format: DumpText})
+
+		expectedDump := "Database.Host = localhost\n" +
+			"Database.Password = ******\n" +
+			"Credentials.Token = ******\n" +
+			"Port = 8080\n"
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedDump, dump)
+	})
+
+	t.Run("should dump text with provenance", func(t *testing.T) {
+		c := newDumpTestConfig()
+		provenance := Provenance{
+			"Database.Host": {Type: SourceFile, Name: "cfg.prod.env", Step: 1},
+		}
+
+		dump, err := Dump(c, DumpOptions{Format: DumpText, Provenance: provenance})
+
+		expectedDump := "Database.Host = localhost (file 'cfg.prod.env' (step 1))\n" +
+			"Database.Password = ******\n" +
+			"Credentials.Token = ******\n" +
+			"Port = 8080\n"
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedDump, dump)
+	})
+
+	t.Run("should dump json", func(t *testing.T) {
+		c := newDumpTestConfig()
+
+		dump, err := Dump(&c, DumpOptions{Format: DumpJSON})
+
+		expectedDump := `{
+  "Credentials": {
+    "Token": "******"
+  },
+  "Database": {
+    "Host": "localhost",
+    "Password": "******"
+  },
+  "Port": 8080
+}`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedDump, dump)
+	})
+
+	t.Run("should dump yaml with provenance", func(t *testing.T) {
+		c := newDumpTestConfig()
+		provenance := Provenance{
+			"Port": {Type: SourceEnvVariable, Name: "PORT", Step: 2},
+		}
+
+		dump, err := Dump(&c, DumpOptions{Format: DumpYAML, Provenance: provenance})
+
+		expectedDump := `Credentials:
+  Token:
+    source: unchanged
+    value: '******'
+Database:
+  Host:
+    source: unchanged
+    value: localhost
+  Password:
+    source: unchanged
+    value: '******'
+Port:
+  source: environment variable 'PORT' (step 2)
+  value: 8080
+`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedDump, dump)
+	})
+
+	t.Run("should not leak secrets of pointers, slices and maps", func(t *testing.T) {
+		type db struct {
+			Host     string
+			Password string `secret:"true"`
+		}
+
+		c := struct {
+			DB       *db
+			NilDB    *db
+			Replicas []db
+			Empty    []db
+			ByName   map[string]*db
+			Extra    interface{}
+			Token    *string `secret:"true"`
+		}{
+			DB:       &db{Host: "h", Password: "hunter2"},
+			Replicas: []db{{Host: "r1", Password: "hunter2"}, {Host: "r2", Password: "hunter2"}},
+			Empty:    []db{},
+			ByName:   map[string]*db{"primary": {Host: "p", Password: "hunter2"}},
+			Extra:    db{Host: "e", Password: "hunter2"},
+		}
+
+		for _, format := range []DumpFormat{DumpText, DumpJSON, DumpYAML} {
+			dump, err := Dump(&c, DumpOptions{Format: format})
+			require.NoError(t, err)
+			assert.NotContains(t, dump, "hunter2")
+		}
+
+		dump, err := Dump(&c, DumpOptions{Format: DumpText})
+		expectedDump := "DB.Host = h\n" +
+			"DB.Password = ******\n" +
+			"NilDB = <nil>\n" +
+			"Replicas[0].Host = r1\n" +
+			"Replicas[0].Password = ******\n" +
+			"Replicas[1].Host = r2\n" +
+			"Replicas[1].Password = ******\n" +
+			"Empty = []\n" +
+			"ByName[primary].Host = p\n" +
+			"ByName[primary].Password = ******\n" +
+			"Extra = ******\n" +
+			"Token = ******\n"
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedDump, dump)
+
+		dump, err = Dump(&c, DumpOptions{Format: DumpJSON})
+		expectedJSONDump := `{
+  "ByName": {
+    "primary": {
+      "Host": "p",
+      "Password": "******"
+    }
+  },
+  "DB": {
+    "Host": "h",
+    "Password": "******"
+  },
+  "Empty": [],
+  "Extra": "******",
+  "NilDB": null,
+  "Replicas": [
+    {
+      "Host": "r1",
+      "Password": "******"
+    },
+    {
+      "Host": "r2",
+      "Password": "******"
+    }
+  ],
+  "Token": "******"
+}`
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedJSONDump, dump)
+	})
+
+	t.Run("should return errors for invalid input", func(t *testing.T) {
+		_, err := Dump("config", DumpOptions{})
+		assert.Equal(t, ErrNoConfigStruct, err)
+
+		c := newDumpTestConfig()
+		_, err = Dump(&c, DumpOptions{Format: DumpFormat(42)})
+		require.Equal(t, ErrUnknownDumpFormat, err)
+	})
+}
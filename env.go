@@ -3,11 +3,11 @@ package yetenv
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
-
-	"github.com/ilyakaznacheev/cleanenv"
+	"time"
 )
 
 const (
@@ -20,6 +20,8 @@ const (
 	defaultStagingConfigFile    = "cfg.staging"
 	defaultProductionConfigFile = "cfg.prod"
 	defaultCustomConfigFile     = "cfg"
+
+	localConfigFileSuffix = ".local"
 )
 
 // Environment defines the environment of an application (e.g. Develop, Staging, Production, etc.)
@@ -73,13 +75,18 @@ const (
 	LoadBehaviorUnknown LoadBehavior = iota
 	LoadBehaviorDefault
 	LoadBehaviorCustom
+	LoadBehaviorCascading
 )
 
 // DefaultVariableName defines the default name of the environment variable.
 var DefaultVariableName = "ENVIRONMENT"
 
 var (
-	ErrUnknownLoadBehavior = errors.New("load behavior is unknown - only default or custom load behavior is allowed")
+	ErrUnknownLoadBehavior      = errors.New("load behavior is unknown - only default, custom or cascading load behavior is allowed")
+	ErrEnvironmentVariableEmpty = errors.New("environment variable is empty")
+	ErrUnknownEnvironmentValue  = errors.New("environment variable value is unknown")
+	ErrConfigFileNotFound       = errors.New("config file not found")
+	ErrAmbiguousConfigFile      = errors.New("config file exists in multiple formats")
 )
 
 // GetEnvironment returns the current Environment value depending on the OS environment
@@ -96,47 +103,180 @@ func GetEnvironmentFromVariable(variableName string) Environment {
 	return environmentFromVariableValue(envRaw)
 }
 
+// GetEnvironmentFromVariables checks the provided variable names in order and returns the Environment value of the
+// first variable which is set to a non-empty value, together with the name of that variable.
+// If none of the variables is set, it returns Develop (or BuildEnvironment) and an empty variable name.
+// The variable name is empty as well when BuildEnvironment wins over the variable.
+func GetEnvironmentFromVariables(variableNames ...string) (Environment, string) {
+	if environment, ok := buildEnvironmentHasPrecedence(); ok {
+		return environment, ""
+	}
+
+	for _, variableName := range variableNames {
+		envRaw := os.Getenv(variableName)
+		if normalizeVariableValue(envRaw) == "" {
+			continue
+		}
+
+		if environment, ok := lookupEnvironment(envRaw); ok {
+			return environment, variableName
+		}
+
+		if environment, ok := buildEnvironment(); ok {
+			return environment, ""
+		}
+
+		return Develop, variableName
+	}
+
+	return fallbackEnvironment(), ""
+}
+
+// GetEnvironmentStrict works like GetEnvironment but returns an *EnvironmentError when the variable defined by
+// DefaultVariableName contains an unknown value or is empty and no BuildEnvironment is set.
+func GetEnvironmentStrict() (Environment, error) {
+	return GetEnvironmentFromVariableStrict(DefaultVariableName)
+}
+
+// GetEnvironmentFromVariableStrict works like GetEnvironmentFromVariable but returns an *EnvironmentError when the
+// variable provided by the parameter contains an unknown value or is empty and no BuildEnvironment is set.
+func GetEnvironmentFromVariableStrict(variableName string) (Environment, error) {
+	if environment, ok := buildEnvironmentHasPrecedence(); ok {
+		return environment, nil
+	}
+
+	envRaw := os.Getenv(variableName)
+	if normalizeVariableValue(envRaw) == "" {
+		if environment, ok := buildEnvironment(); ok {
+			return environment, nil
+		}
+
+		return "", &EnvironmentError{VariableName: variableName, Value: envRaw, Err: ErrEnvironmentVariableEmpty}
+	}
+
+	environment, ok := lookupEnvironment(envRaw)
+	if !ok {
+		return "", &EnvironmentError{VariableName: variableName, Value: envRaw, Err: ErrUnknownEnvironmentValue}
+	}
+
+	return environment, nil
+}
+
+// EnvironmentError is returned by the strict environment detection. Err is either ErrEnvironmentVariableEmpty or
+// ErrUnknownEnvironmentValue, so it can be checked with errors.Is.
+type EnvironmentError struct {
+	VariableName string
+	Value        string
+	Err          error
+}
+
+func (e *EnvironmentError) Error() string {
+	if e.VariableName == "" {
+		return fmt.Sprintf("unknown environment '%s'", e.Value)
+	}
+
+	if e.Err == ErrEnvironmentVariableEmpty {
+		return fmt.Sprintf("environment variable '%s' is empty", e.VariableName)
+	}
+
+	return fmt.Sprintf("environment variable '%s' has unknown value '%s'", e.VariableName, e.Value)
+}
+
+func (e *EnvironmentError) Unwrap() error {
+	return e.Err
+}
+
 func environmentFromVariableValue(variableValue string) Environment {
-	env := strings.ToLower(variableValue)
+	if environment, ok := buildEnvironmentHasPrecedence(); ok {
+		return environment
+	}
+
+	if environment, ok := lookupEnvironment(variableValue); ok {
+		return environment
+	}
+
+	return fallbackEnvironment()
+}
+
+func lookupEnvironment(variableValue string) (Environment, bool) {
+	env := normalizeVariableValue(variableValue)
 
-	switch env {
-	case environmentVariableValueProduction:
-		return Production
-	case environmentVariableValueStaging:
-		return Staging
-	case environmentVariableValueTest:
-		return Test
+	if aliased, ok := environmentFromAlias(env); ok {
+		return aliased, true
 	}
 
-	return Develop
+	return registeredEnvironmentFromVariableValue(env)
 }
 
+// SearchMode defines how config files are resolved when using multiple search paths.
+type SearchMode int
+
+const (
+	// SearchModeFirstMatch loads a config file only from the first search path which contains it.
+	SearchModeFirstMatch SearchMode = iota
+	// SearchModeMergeAll loads a config file from every search path which contains it. Files of earlier search paths
+	// are loaded last, so they override files of later search paths.
+	SearchModeMergeAll
+)
+
 // ConfigLoader loads configuration values from files and the OS environment into a configuration struct.
 // It uses the builder pattern and needs to be extecuted by the finishing method.
 type ConfigLoader struct {
-	LoadPath      string
-	FileExtension ConfigFileExtension
-	ConfigFiles   map[Environment]string
-	Environment   Environment
-	LoadBehavior  LoadBehavior
-	loadOrder     []loadOrderItem
+	LoadPath                   string
+	FileExtension              ConfigFileExtension
+	ConfigFiles                map[Environment]string
+	Environment                Environment
+	LoadBehavior               LoadBehavior
+	StrictEnvironmentDetection bool
+	EnvironmentDetector        EnvironmentDetector
+	RequiredEnvironments       map[Environment]bool
+	DetectFileFormat           bool
+	FileExtensionPriority      []ConfigFileExtension
+	SearchPaths                []string
+	SearchMode                 SearchMode
+	LoadPathResolution         LoadPathResolution
+	ProjectRootMarkers         []string
+	FileSystem                 fs.FS
+	WatchInterval              time.Duration
+	Interpolation              bool
+	SecretResolution           bool
+	Validation                 bool
+	loadPathErr                error
+	resolvedPaths              []string
+	provenance                 *provenanceTracker
+	interpolationVariables     map[string]string
+	dotenvOriginals            map[string]*string
+	pendingDotenvVariables     map[string]string
+	loadOrder                  []loadOrderItem
+	reloadFuncs                []ReloadFunc
+	reloadErrorFuncs           []ReloadErrorFunc
 }
 
 // NewConfigLoader initializes a new ConfigLoader builder.
+// Config files of registered environments are added to the default config files.
 func NewConfigLoader() *ConfigLoader {
+	configFiles := map[Environment]string{
+		Develop:    defaultDevelopConfigFile,
+		Test:       defaultTestConfigFile,
+		Staging:    defaultStagingConfigFile,
+		Production: defaultProductionConfigFile,
+		Custom:     defaultCustomConfigFile,
+	}
+
+	for environment, configFile := range registeredConfigFiles() {
+		configFiles[environment] = configFile
+	}
+
 	return &ConfigLoader{
-		LoadPath:      "./",
-		FileExtension: DOTENV,
-		ConfigFiles: map[Environment]string{
-			Develop:    defaultDevelopConfigFile,
-			Test:       defaultTestConfigFile,
-			Staging:    defaultStagingConfigFile,
-			Production: defaultProductionConfigFile,
-			Custom:     defaultCustomConfigFile,
-		},
-		Environment:  "",
-		LoadBehavior: LoadBehaviorUnknown,
-		loadOrder:    []loadOrderItem{},
+		LoadPath:              "./",
+		FileExtension:         DOTENV,
+		ConfigFiles:           configFiles,
+		Environment:           "",
+		LoadBehavior:          LoadBehaviorUnknown,
+		RequiredEnvironments:  map[Environment]bool{},
+		FileExtensionPriority: []ConfigFileExtension{DOTENV, YAML, JSON, TOML},
+		ProjectRootMarkers:    DefaultProjectRootMarkers,
+		loadOrder:             []loadOrderItem{},
 	}
 }
 
@@ -153,6 +293,38 @@ func (c *ConfigLoader) UseFileProcessor(extension ConfigFileExtension) *ConfigLo
 	return c
 }
 
+// UseSearchPaths can be used to look for config files in multiple directories instead of the single LoadPath.
+// The search paths are tried in order and are resolved depending on the SearchMode (Default: SearchModeFirstMatch).
+// It applies to the default and cascading load behavior and to LoadFromFileForEnvironment calls made afterwards.
+func (c *ConfigLoader) UseSearchPaths(paths ...string) *ConfigLoader {
+	c.SearchPaths = paths
+	return c
+}
+
+// UseSearchMode can be used to change the SearchMode for multiple search paths.
+func (c *ConfigLoader) UseSearchMode(mode SearchMode) *ConfigLoader {
+	c.SearchMode = mode
+	return c
+}
+
+// ResolvedPaths returns the paths of all config files which were loaded by the last LoadInto call in load order.
+func (c *ConfigLoader) ResolvedPaths() []string {
+	return c.resolvedPaths
+}
+
+// UseFileFormatDetection makes LoadFromFileForEnvironment look for the config file of an environment with every
+// extension of the priority order instead of using a single file processor. The priority order defaults to
+// DOTENV, YAML, JSON, TOML and only the provided extensions are considered when a priority order is provided.
+// LoadInto returns a *ConfigFileError wrapping ErrAmbiguousConfigFile when a config file exists in more than one format.
+func (c *ConfigLoader) UseFileFormatDetection(priority ...ConfigFileExtension) *ConfigLoader {
+	c.DetectFileFormat = true
+	if len(priority) > 0 {
+		c.FileExtensionPriority = priority
+	}
+
+	return c
+}
+
 // UseFileNameForEnvironment can be used to change the config file name for a specific environment.
 // Default file names are:
 // Develop     -> 'cfg.dev'
@@ -174,6 +346,17 @@ func (c *ConfigLoader) UseFileNameForEnvironment(environment Environment, fileNa
 	return c
 }
 
+// RequireFileForEnvironment marks the config files of the provided environments as required, so LoadInto returns
+// a *ConfigFileError wrapping ErrConfigFileNotFound when the file of the current environment does not exist.
+// It applies to the default load behavior and to LoadFromFileForEnvironment calls made afterwards.
+func (c *ConfigLoader) RequireFileForEnvironment(environments ...Environment) *ConfigLoader {
+	for _, environment := range environments {
+		c.RequiredEnvironments[environment] = true
+	}
+
+	return c
+}
+
 // UseEnvironment can be used to change the current environment value of the ConfigLoader.
 // Defaults to the value of the ENVIRONMENT variable.
 func (c *ConfigLoader) UseEnvironment(environment Environment) *ConfigLoader {
@@ -181,6 +364,21 @@ func (c *ConfigLoader) UseEnvironment(environment Environment) *ConfigLoader {
 	return c
 }
 
+// UseStrictEnvironmentDetection makes LoadInto use GetEnvironmentStrict when no environment was injected,
+// so LoadInto fails for an empty or unknown environment variable value.
+func (c *ConfigLoader) UseStrictEnvironmentDetection() *ConfigLoader {
+	c.StrictEnvironmentDetection = true
+	return c
+}
+
+// UseEnvironmentDetector can be used to detect the environment by an EnvironmentDetector instead of GetEnvironment
+// when no environment was injected. If the detector is not able to detect the environment, Develop is used or
+// ErrEnvironmentNotDetected is returned when using strict environment detection.
+func (c *ConfigLoader) UseEnvironmentDetector(detector EnvironmentDetector) *ConfigLoader {
+	c.EnvironmentDetector = detector
+	return c
+}
+
 // UseLoadBehavior can be used to set a LoadBehavior to a specific value.
 func (c *ConfigLoader) UseLoadBehavior(behavior LoadBehavior) *ConfigLoader {
 	c.LoadBehavior = behavior
@@ -199,29 +397,41 @@ func (c *ConfigLoader) UseCustomLoadBehavior() *ConfigLoader {
 	return c
 }
 
+// UseCascadingLoadBehavior sets load behavior to LoadBehaviorCascading. It loads 'cfg', then 'cfg.<environment>',
+// then 'cfg.local' and then 'cfg.<environment>.local', so later files override earlier ones.
+// The '.local' files are skipped in the Test environment.
+func (c *ConfigLoader) UseCascadingLoadBehavior() *ConfigLoader {
+	c.UseLoadBehavior(LoadBehaviorCascading)
+	return c
+}
+
 // LoadFromFileForEnvironment can be used to reuse environmental load logic for a custom load behavior.
 // For example: LoadFromFileForEnvironment(Develop) will behave the same as in the default load behavior.
+// Environments without a config file name are ignored.
 func (c *ConfigLoader) LoadFromFileForEnvironment(environment Environment) *ConfigLoader {
-	configFileName := c.ConfigFiles[environment]
-	if c.FileExtension == DOTENV && configFileName == defaultCustomConfigFile {
-		configFileName = ""
+	configFileName, ok := c.ConfigFiles[environment]
+	if !ok {
+		return c
 	}
 
-	fullFilePath := c.composeFilePath(c.LoadPath, configFileName, c.FileExtension)
-
+	var conditionFunc ConditionalLoadFunc
 	switch environment {
 	case Develop:
-		c.LoadFromConditionalFile(fullFilePath, DefaultConditionForDevelopEnvironment)
+		conditionFunc = DefaultConditionForDevelopEnvironment
 	case Test:
-		c.LoadFromConditionalFile(fullFilePath, DefaultConditionForTestEnvironment)
+		conditionFunc = DefaultConditionForTestEnvironment
 	case Staging:
-		c.LoadFromConditionalFile(fullFilePath, DefaultConditionForStagingEnvironment)
+		conditionFunc = DefaultConditionForStagingEnvironment
 	case Production:
-		c.LoadFromConditionalFile(fullFilePath, DefaultConditionForProductionEnvironment)
+		conditionFunc = DefaultConditionForProductionEnvironment
 	case Custom:
-		c.LoadFromFile(fullFilePath)
+		conditionFunc = nil
+	default:
+		conditionFunc = conditionForEnvironment(environment)
 	}
 
+	c.loadFromConfigFileName(configFileName, conditionFunc, c.RequiredEnvironments[environment])
+
 	return c
 }
 
@@ -236,6 +446,18 @@ func (c *ConfigLoader) LoadFromFile(filePath string) *ConfigLoader {
 	return c
 }
 
+// LoadFromRequiredFile works like LoadFromFile, but LoadInto returns a *ConfigFileError wrapping
+// ErrConfigFileNotFound when the file does not exist.
+func (c *ConfigLoader) LoadFromRequiredFile(filePath string) *ConfigLoader {
+	c.loadOrder = append(c.loadOrder, loadOrderItem{
+		file:          filePath,
+		conditionFunc: nil,
+		required:      true,
+	})
+
+	return c
+}
+
 // LoadFromConditionalFile can be used to load a config file only when the condition of the conditionFunc is met.
 // It will not use the LoadPath, so the full path to config file should be provided.
 func (c *ConfigLoader) LoadFromConditionalFile(filePath string, conditionFunc ConditionalLoadFunc) *ConfigLoader {
@@ -247,17 +469,59 @@ func (c *ConfigLoader) LoadFromConditionalFile(filePath string, conditionFunc Co
 	return c
 }
 
+// LoadFromRequiredConditionalFile works like LoadFromConditionalFile, but LoadInto returns a *ConfigFileError wrapping
+// ErrConfigFileNotFound when the condition is met and the file does not exist.
+func (c *ConfigLoader) LoadFromRequiredConditionalFile(filePath string, conditionFunc ConditionalLoadFunc) *ConfigLoader {
+	c.loadOrder = append(c.loadOrder, loadOrderItem{
+		file:          filePath,
+		conditionFunc: conditionFunc,
+		required:      true,
+	})
+
+	return c
+}
+
 // LoadInto will finish the ConfigLoader and execute the load process. The provided config struct should be a pointer.
+// Secret references are resolved after all load items have been loaded, if enabled by UseSecretResolution.
+// The loaded config is validated by ValidateConfigForEnvironment as final phase, if enabled by UseValidation.
+// Otherwise only the `required-in` tags are checked.
 func (c *ConfigLoader) LoadInto(cfg interface{}) error {
+	if err := c.loadItems(cfg); err != nil {
+		return err
+	}
+
+	if c.SecretResolution {
+		if err := resolveSecrets(cfg); err != nil {
+			return err
+		}
+	}
+
+	return validateConfig(cfg, c.Environment, c.Validation)
+}
+
+// loadItems executes the load order into the config struct.
+func (c *ConfigLoader) loadItems(cfg interface{}) error {
 	switch c.LoadBehavior {
 	case LoadBehaviorUnknown:
 		return ErrUnknownLoadBehavior
 	case LoadBehaviorDefault:
 		c.setupDefaultLoadBehavior()
+	case LoadBehaviorCascading:
+		c.setupCascadingLoadBehavior()
+	}
+
+	if c.loadPathErr != nil {
+		return c.loadPathErr
 	}
 
+	c.resolvedPaths = []string{}
+	c.provenance = newProvenanceTracker()
+	c.interpolationVariables = map[string]string{}
+
 	if c.Environment == "" {
-		c.Environment = GetEnvironment()
+		if err := c.detectEnvironment(); err != nil {
+			return err
+		}
 	}
 
 	for _, loadItem := range c.loadOrder {
@@ -270,7 +534,7 @@ func (c *ConfigLoader) LoadInto(cfg interface{}) error {
 			continue
 		}
 
-		err := c.loadConfigFromFile(loadItem.file, cfg)
+		err := c.loadConfigFromItem(loadItem, cfg)
 		if err != nil {
 			return err
 		}
@@ -279,14 +543,124 @@ func (c *ConfigLoader) LoadInto(cfg interface{}) error {
 	return nil
 }
 
-func (c *ConfigLoader) loadConfigFromFile(file string, cfg interface{}) error {
-	if fileExists(file) {
-		return cleanenv.ReadConfig(file, cfg)
+func (c *ConfigLoader) detectEnvironment() error {
+	if c.EnvironmentDetector != nil {
+		environment, ok := c.EnvironmentDetector.DetectEnvironment()
+		if ok {
+			c.Environment = environment
+			return nil
+		}
+
+		if c.StrictEnvironmentDetection {
+			return ErrEnvironmentNotDetected
+		}
+
+		c.Environment = fallbackEnvironment()
+		return nil
+	}
+
+	if !c.StrictEnvironmentDetection {
+		c.Environment = GetEnvironment()
+		return nil
 	}
 
+	environment, err := GetEnvironmentStrict()
+	if err != nil {
+		return err
+	}
+
+	c.Environment = environment
 	return nil
 }
 
+func (c *ConfigLoader) loadConfigFromItem(loadItem loadOrderItem, cfg interface{}) error {
+	if loadItem.source != nil {
+		return c.loadConfigFromSource(loadItem.source, cfg)
+	}
+
+	return c.loadConfigFromFile(loadItem, cfg)
+}
+
+func (c *ConfigLoader) loadConfigFromFile(loadItem loadOrderItem, cfg interface{}) error {
+	files, err := c.resolveLoadItemFiles(loadItem)
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 && loadItem.required {
+		return &ConfigFileError{Path: loadItem.file, Err: ErrConfigFileNotFound}
+	}
+
+	for _, file := range files {
+		if err := c.readConfigFile(file, cfg); err != nil {
+			return err
+		}
+
+		c.resolvedPaths = append(c.resolvedPaths, file)
+	}
+
+	return nil
+}
+
+func (c *ConfigLoader) resolveLoadItemFiles(loadItem loadOrderItem) ([]string, error) {
+	if len(loadItem.candidates) == 0 {
+		if c.fileExists(loadItem.file) {
+			return []string{loadItem.file}, nil
+		}
+
+		return nil, nil
+	}
+
+	var files []string
+	for _, searchPathCandidates := range loadItem.candidates {
+		file, ok, err := c.resolveCandidates(loadItem, searchPathCandidates)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if c.SearchMode != SearchModeMergeAll {
+			return []string{file}, nil
+		}
+
+		files = append([]string{file}, files...)
+	}
+
+	return files, nil
+}
+
+func (c *ConfigLoader) resolveCandidates(loadItem loadOrderItem, candidates []string) (string, bool, error) {
+	var existingFiles []string
+	for _, candidate := range candidates {
+		if c.fileExists(candidate) {
+			existingFiles = append(existingFiles, candidate)
+		}
+	}
+
+	switch len(existingFiles) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return existingFiles[0], true, nil
+	}
+
+	return "", false, &ConfigFileError{
+		Path: loadItem.file,
+		Err:  fmt.Errorf("%w: %s", ErrAmbiguousConfigFile, strings.Join(existingFiles, ", ")),
+	}
+}
+
+func (c *ConfigLoader) composeEnvironmentFilePath(loadPath string, configFileName string, fileExtension ConfigFileExtension) string {
+	if fileExtension == DOTENV && configFileName == defaultCustomConfigFile {
+		configFileName = ""
+	}
+
+	return c.composeFilePath(loadPath, configFileName, fileExtension)
+}
+
 func (c *ConfigLoader) composeFilePath(loadPath string, fileName string, fileExtension ConfigFileExtension) string {
 	fileName = strings.TrimRight(fileName, ".")
 	fullFileName := fmt.Sprintf("%s%s", fileName, string(fileExtension))
@@ -294,14 +668,88 @@ func (c *ConfigLoader) composeFilePath(loadPath string, fileName string, fileExt
 	return filepath.Join(loadPath, fullFileName)
 }
 
+func (c *ConfigLoader) loadFromConfigFileName(configFileName string, conditionFunc ConditionalLoadFunc, required bool) {
+	fileExtensions := []ConfigFileExtension{c.FileExtension}
+	if c.DetectFileFormat {
+		fileExtensions = c.FileExtensionPriority
+	}
+
+	searchPaths, err := c.resolveSearchPaths()
+	if err != nil {
+		c.loadPathErr = err
+		return
+	}
+
+	candidates := make([][]string, 0, len(searchPaths))
+	for _, searchPath := range searchPaths {
+		searchPathCandidates := make([]string, 0, len(fileExtensions))
+		for _, fileExtension := range fileExtensions {
+			searchPathCandidates = append(searchPathCandidates, c.composeEnvironmentFilePath(searchPath, configFileName, fileExtension))
+		}
+
+		candidates = append(candidates, searchPathCandidates)
+	}
+
+	fullFilePath := c.composeEnvironmentFilePath(searchPaths[0], configFileName, c.FileExtension)
+	if c.DetectFileFormat {
+		fullFilePath = c.composeFilePath(searchPaths[0], configFileName, "")
+	}
+
+	c.loadOrder = append(c.loadOrder, loadOrderItem{
+		file:          fullFilePath,
+		candidates:    candidates,
+		conditionFunc: conditionFunc,
+		required:      required,
+	})
+}
+
 func (c *ConfigLoader) setupDefaultLoadBehavior() {
 	c.loadOrder = []loadOrderItem{}
+	c.loadPathErr = nil
+
+	for _, environment := range environmentsForLoadBehavior() {
+		c.LoadFromFileForEnvironment(environment)
+	}
+
+	c.LoadFromFileForEnvironment(Custom)
+}
+
+func (c *ConfigLoader) setupCascadingLoadBehavior() {
+	c.loadOrder = []loadOrderItem{}
+	c.loadPathErr = nil
 
-	c.LoadFromFileForEnvironment(Develop)
-	c.LoadFromFileForEnvironment(Test)
-	c.LoadFromFileForEnvironment(Staging)
-	c.LoadFromFileForEnvironment(Production)
 	c.LoadFromFileForEnvironment(Custom)
+
+	environments := environmentsForLoadBehavior()
+	for _, environment := range environments {
+		c.LoadFromFileForEnvironment(environment)
+	}
+
+	c.loadFromConfigFileName(c.ConfigFiles[Custom]+localConfigFileSuffix, conditionForLocalFiles, false)
+
+	for _, environment := range environments {
+		configFileName, ok := c.ConfigFiles[environment]
+		if !ok {
+			continue
+		}
+
+		c.loadFromConfigFileName(configFileName+localConfigFileSuffix, conditionForLocalEnvironmentFile(environment), false)
+	}
+}
+
+func environmentsForLoadBehavior() []Environment {
+	environments := []Environment{Develop, Test, Staging, Production}
+	return append(environments, RegisteredEnvironments()...)
+}
+
+func conditionForLocalFiles(configLoader *ConfigLoader, currentEnvironment Environment) bool {
+	return currentEnvironment != Test
+}
+
+func conditionForLocalEnvironmentFile(environment Environment) ConditionalLoadFunc {
+	return func(configLoader *ConfigLoader, currentEnvironment Environment) bool {
+		return currentEnvironment == environment && conditionForLocalFiles(configLoader, currentEnvironment)
+	}
 }
 
 func fileExists(filename string) bool {
@@ -314,5 +762,23 @@ func fileExists(filename string) bool {
 
 type loadOrderItem struct {
 	file          string
+	candidates    [][]string
+	source        *readerSource
 	conditionFunc ConditionalLoadFunc
+	required      bool
+}
+
+// ConfigFileError is returned by LoadInto when a config file could not be loaded. Err can be checked with errors.Is
+// (e.g. ErrConfigFileNotFound).
+type ConfigFileError struct {
+	Path string
+	Err  error
+}
+
+func (e *ConfigFileError) Error() string {
+	return fmt.Sprintf("config file '%s': %s", e.Path, e.Err)
+}
+
+func (e *ConfigFileError) Unwrap() error {
+	return e.Err
 }
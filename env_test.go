@@ -1,8 +1,10 @@
 package yetenv
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -217,6 +219,117 @@ func TestGetEnvironmentFromVariable(t *testing.T) {
 	})
 }
 
+func TestGetEnvironmentFromVariables(t *testing.T) {
+	variableNames := []string{"CHAIN_APP_ENV", "CHAIN_GO_ENV", "CHAIN_STAGE"}
+
+	unsetVariables := func() {
+		for _, variableName := range variableNames {
+			_ = os.Unsetenv(variableName)
+		}
+	}
+
+	t.Run("should return develop and empty variable name when no variable is set", func(t *testing.T) {
+		unsetVariables()
+
+		actualEnv, actualVariableName := GetEnvironmentFromVariables(variableNames...)
+		assert.Equal(t, Develop, actualEnv)
+		assert.Equal(t, "", actualVariableName)
+	})
+
+	t.Run("should use the first variable which is set", func(t *testing.T) {
+		unsetVariables()
+		defer unsetVariables()
+
+		require.NoError(t, os.Setenv("CHAIN_APP_ENV", ""))
+		require.NoError(t, os.Setenv("CHAIN_GO_ENV", "staging"))
+		require.NoError(t, os.Setenv("CHAIN_STAGE", "production"))
+
+		actualEnv, actualVariableName := GetEnvironmentFromVariables(variableNames...)
+		assert.Equal(t, Staging, actualEnv)
+		assert.Equal(t, "CHAIN_GO_ENV", actualVariableName)
+	})
+
+	t.Run("should return empty variable name when build environment wins", func(t *testing.T) {
+		unsetVariables()
+		defer unsetVariables()
+
+		oldBuildEnvironment, oldPrecedence := BuildEnvironment, BuildEnvironmentPrecedence
+		defer func() {
+			BuildEnvironment, BuildEnvironmentPrecedence = oldBuildEnvironment, oldPrecedence
+		}()
+
+		BuildEnvironment = "production"
+		require.NoError(t, os.Setenv("CHAIN_GO_ENV", "staging"))
+
+		actualEnv, actualVariableName := GetEnvironmentFromVariables(variableNames...)
+		assert.Equal(t, Staging, actualEnv)
+		assert.Equal(t, "CHAIN_GO_ENV", actualVariableName)
+
+		BuildEnvironmentPrecedence = PrecedenceBuild
+
+		actualEnv, actualVariableName = GetEnvironmentFromVariables(variableNames...)
+		assert.Equal(t, Production, actualEnv)
+		assert.Equal(t, "", actualVariableName)
+
+		BuildEnvironmentPrecedence = PrecedenceVariable
+		require.NoError(t, os.Setenv("CHAIN_GO_ENV", "unknown"))
+
+		actualEnv, actualVariableName = GetEnvironmentFromVariables(variableNames...)
+		assert.Equal(t, Production, actualEnv)
+		assert.Equal(t, "", actualVariableName)
+	})
+}
+
+func TestGetEnvironmentFromVariableStrict(t *testing.T) {
+	variableName := "STRICT_ENV"
+
+	t.Run("should return environment for known values", func(t *testing.T) {
+		err := os.Setenv(variableName, "PROD")
+		require.NoError(t, err)
+
+		actualEnv, err := GetEnvironmentFromVariableStrict(variableName)
+		assert.NoError(t, err)
+		assert.Equal(t, Production, actualEnv)
+	})
+
+	t.Run("should return error for empty value", func(t *testing.T) {
+		err := os.Unsetenv(variableName)
+		require.NoError(t, err)
+
+		_, err = GetEnvironmentFromVariableStrict(variableName)
+
+		var environmentErr *EnvironmentError
+		require.True(t, errors.As(err, &environmentErr))
+		assert.Equal(t, variableName, environmentErr.VariableName)
+		assert.True(t, errors.Is(err, ErrEnvironmentVariableEmpty))
+	})
+
+	t.Run("should return error for unknown value", func(t *testing.T) {
+		err := os.Setenv(variableName, "prodution")
+		require.NoError(t, err)
+
+		_, err = GetEnvironmentFromVariableStrict(variableName)
+
+		var environmentErr *EnvironmentError
+		require.True(t, errors.As(err, &environmentErr))
+		assert.Equal(t, "prodution", environmentErr.Value)
+		assert.True(t, errors.Is(err, ErrUnknownEnvironmentValue))
+	})
+}
+
+func TestGetEnvironmentStrict(t *testing.T) {
+	previousVariableName := DefaultVariableName
+	defer func() { DefaultVariableName = previousVariableName }()
+
+	DefaultVariableName = "STRICT_DEFAULT_ENV"
+	err := os.Setenv(DefaultVariableName, "staging")
+	require.NoError(t, err)
+
+	actualEnv, err := GetEnvironmentStrict()
+	assert.NoError(t, err)
+	assert.Equal(t, Staging, actualEnv)
+}
+
 func TestConfigLoader_UseLoadPath(t *testing.T) {
 	configLoader := NewConfigLoader()
 	require.Equal(t, "./", configLoader.LoadPath)
@@ -233,6 +346,41 @@ func TestConfigLoader_UseFileProcessor(t *testing.T) {
 	require.Equal(t, YAML, configLoader.FileExtension)
 }
 
+func TestConfigLoader_UseSearchPaths(t *testing.T) {
+	configLoader := NewConfigLoader()
+	require.Empty(t, configLoader.SearchPaths)
+	require.Equal(t, SearchModeFirstMatch, configLoader.SearchMode)
+
+	configLoader.
+		UseSearchPaths("./config", "/etc/app").
+		UseSearchMode(SearchModeMergeAll).
+		LoadFromFileForEnvironment(Production)
+
+	assert.Equal(t, []string{"./config", "/etc/app"}, configLoader.SearchPaths)
+	assert.Equal(t, SearchModeMergeAll, configLoader.SearchMode)
+	assert.Equal(t, filepath.Join("config", "cfg.prod.env"), configLoader.loadOrder[0].file)
+	assert.Equal(t, [][]string{
+		{filepath.Join("config", "cfg.prod.env")},
+		{filepath.Join("/etc/app", "cfg.prod.env")},
+	}, configLoader.loadOrder[0].candidates)
+}
+
+func TestConfigLoader_UseFileFormatDetection(t *testing.T) {
+	configLoader := NewConfigLoader()
+	require.False(t, configLoader.DetectFileFormat)
+	require.Equal(t, []ConfigFileExtension{DOTENV, YAML, JSON, TOML}, configLoader.FileExtensionPriority)
+
+	configLoader.UseFileFormatDetection()
+	assert.True(t, configLoader.DetectFileFormat)
+	assert.Equal(t, []ConfigFileExtension{DOTENV, YAML, JSON, TOML}, configLoader.FileExtensionPriority)
+
+	configLoader.UseFileFormatDetection(YAML, TOML)
+	assert.Equal(t, []ConfigFileExtension{YAML, TOML}, configLoader.FileExtensionPriority)
+
+	configLoader.LoadFromFileForEnvironment(Custom)
+	assert.Equal(t, [][]string{{"cfg.yaml", "cfg.toml"}}, configLoader.loadOrder[0].candidates)
+}
+
 func TestConfigLoader_UseFileNameForEnvironment(t *testing.T) {
 	configLoader := NewConfigLoader()
 	require.Equal(t, defaultDevelopConfigFile, configLoader.ConfigFiles[Develop])
@@ -258,6 +406,20 @@ func TestConfigLoader_UseFileNameForEnvironment(t *testing.T) {
 	assert.Equal(t, "your.dev", configLoader.ConfigFiles[Develop])
 }
 
+func TestConfigLoader_RequireFileForEnvironment(t *testing.T) {
+	configLoader := NewConfigLoader()
+	require.Empty(t, configLoader.RequiredEnvironments)
+
+	configLoader.
+		RequireFileForEnvironment(Staging, Production).
+		LoadFromFileForEnvironment(Production).
+		LoadFromFileForEnvironment(Develop)
+
+	assert.Equal(t, map[Environment]bool{Staging: true, Production: true}, configLoader.RequiredEnvironments)
+	assert.True(t, configLoader.loadOrder[0].required)
+	assert.False(t, configLoader.loadOrder[1].required)
+}
+
 func TestConfigLoader_UseEnvironment(t *testing.T) {
 	configLoader := NewConfigLoader()
 	require.Equal(t, Environment(""), configLoader.Environment)
@@ -266,6 +428,14 @@ func TestConfigLoader_UseEnvironment(t *testing.T) {
 	assert.Equal(t, Staging, configLoader.Environment)
 }
 
+func TestConfigLoader_UseStrictEnvironmentDetection(t *testing.T) {
+	configLoader := NewConfigLoader()
+	require.False(t, configLoader.StrictEnvironmentDetection)
+
+	configLoader.UseStrictEnvironmentDetection()
+	assert.True(t, configLoader.StrictEnvironmentDetection)
+}
+
 func TestConfigLoader_UseLoadBehavior(t *testing.T) {
 	configLoader := NewConfigLoader()
 	require.Equal(t, LoadBehaviorUnknown, configLoader.LoadBehavior)
@@ -293,6 +463,14 @@ func TestConfigLoader_UseCustomLoadBehavior(t *testing.T) {
 	assert.Equal(t, LoadBehaviorCustom, configLoader.LoadBehavior)
 }
 
+func TestConfigLoader_UseCascadingLoadBehavior(t *testing.T) {
+	configLoader := NewConfigLoader()
+	require.Equal(t, LoadBehaviorUnknown, configLoader.LoadBehavior)
+
+	configLoader.UseCascadingLoadBehavior()
+	assert.Equal(t, LoadBehaviorCascading, configLoader.LoadBehavior)
+}
+
 func TestConfigLoader_LoadFromFileForEnvironment(t *testing.T) {
 	configLoader := NewConfigLoader()
 	require.Equal(t, len(configLoader.loadOrder), 0)
@@ -362,6 +540,30 @@ func TestConfigLoader_LoadFromFile(t *testing.T) {
 	assert.Nil(t, configLoader.loadOrder[0].conditionFunc)
 }
 
+func TestConfigLoader_LoadFromRequiredFile(t *testing.T) {
+	configLoader := NewConfigLoader()
+	require.Equal(t, len(configLoader.loadOrder), 0)
+
+	configLoader.LoadFromRequiredFile("custom.env")
+
+	assert.Len(t, configLoader.loadOrder, 1)
+	assert.Equal(t, "custom.env", configLoader.loadOrder[0].file)
+	assert.Nil(t, configLoader.loadOrder[0].conditionFunc)
+	assert.True(t, configLoader.loadOrder[0].required)
+}
+
+func TestConfigLoader_LoadFromRequiredConditionalFile(t *testing.T) {
+	configLoader := NewConfigLoader()
+	require.Equal(t, len(configLoader.loadOrder), 0)
+
+	configLoader.LoadFromRequiredConditionalFile("./cfg.dev.env", DefaultConditionForDevelopEnvironment)
+
+	assert.Len(t, configLoader.loadOrder, 1)
+	assert.Equal(t, "./cfg.dev.env", configLoader.loadOrder[0].file)
+	assert.NotNil(t, configLoader.loadOrder[0].conditionFunc)
+	assert.True(t, configLoader.loadOrder[0].required)
+}
+
 func TestConfigLoader_LoadFromConditionalFile(t *testing.T) {
 	configLoader := NewConfigLoader()
 	require.Equal(t, len(configLoader.loadOrder), 0)
@@ -389,6 +591,25 @@ func TestConfigLoader_LoadInto(t *testing.T) {
 		assert.Equal(t, ErrUnknownLoadBehavior, err)
 	})
 
+	t.Run("should return error for unknown environment when using strict environment detection", func(t *testing.T) {
+		resetEnv()
+
+		previousVariableName := DefaultVariableName
+		defer func() { DefaultVariableName = previousVariableName }()
+
+		DefaultVariableName = "STRICT_LOADER_ENV"
+		err := os.Setenv(DefaultVariableName, "prodution")
+		require.NoError(t, err)
+
+		c := testConfig{}
+		err = NewConfigLoader().
+			UseStrictEnvironmentDetection().
+			UseDefaultLoadBehavior().
+			LoadInto(&c)
+
+		assert.True(t, errors.Is(err, ErrUnknownEnvironmentValue))
+	})
+
 	t.Run("default behavior", func(t *testing.T) {
 		t.Run("should load dev and custom config when env=development", func(t *testing.T) {
 			resetEnv()
@@ -507,6 +728,269 @@ func TestConfigLoader_LoadInto(t *testing.T) {
 		})
 	})
 
+	t.Run("cascading behavior", func(t *testing.T) {
+		type cascadeConfig struct {
+			Base             bool   `env:"CASCADE_BASE"`
+			Environment      bool   `env:"CASCADE_ENVIRONMENT"`
+			Local            bool   `env:"CASCADE_LOCAL"`
+			EnvironmentLocal bool   `env:"CASCADE_ENVIRONMENT_LOCAL"`
+			LastFile         string `env:"CASCADE_LAST_FILE"`
+		}
+
+		resetCascadeEnv := func() {
+			_ = os.Unsetenv("CASCADE_BASE")
+			_ = os.Unsetenv("CASCADE_ENVIRONMENT")
+			_ = os.Unsetenv("CASCADE_LOCAL")
+			_ = os.Unsetenv("CASCADE_ENVIRONMENT_LOCAL")
+			_ = os.Unsetenv("CASCADE_LAST_FILE")
+		}
+
+		t.Run("should load base, environment and local files when env=develop", func(t *testing.T) {
+			resetCascadeEnv()
+			defer resetCascadeEnv()
+
+			c := cascadeConfig{}
+			err := NewConfigLoader().
+				UseLoadPath("./testdata/cascade").
+				UseEnvironment(Develop).
+				UseCascadingLoadBehavior().
+				LoadInto(&c)
+
+			expectedConfig := cascadeConfig{
+				Base:             true,
+				Environment:      true,
+				Local:            true,
+				EnvironmentLocal: true,
+				LastFile:         "develop-local",
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, expectedConfig, c)
+		})
+
+		t.Run("should skip local files when env=test", func(t *testing.T) {
+			resetCascadeEnv()
+			defer resetCascadeEnv()
+
+			c := cascadeConfig{}
+			err := NewConfigLoader().
+				UseLoadPath("./testdata/cascade").
+				UseEnvironment(Test).
+				UseCascadingLoadBehavior().
+				LoadInto(&c)
+
+			expectedConfig := cascadeConfig{
+				Base:        true,
+				Environment: true,
+				LastFile:    "test",
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, expectedConfig, c)
+		})
+
+		t.Run("should load base and local file when environment file is missing", func(t *testing.T) {
+			resetCascadeEnv()
+			defer resetCascadeEnv()
+
+			c := cascadeConfig{}
+			err := NewConfigLoader().
+				UseLoadPath("./testdata/cascade").
+				UseEnvironment(Staging).
+				UseCascadingLoadBehavior().
+				LoadInto(&c)
+
+			expectedConfig := cascadeConfig{
+				Base:     true,
+				Local:    true,
+				LastFile: "local",
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, expectedConfig, c)
+		})
+	})
+
+	t.Run("search paths", func(t *testing.T) {
+		type searchConfig struct {
+			First    bool   `env:"SEARCH_FIRST"`
+			Second   bool   `env:"SEARCH_SECOND"`
+			Custom   bool   `env:"SEARCH_CUSTOM"`
+			LastFile string `env:"SEARCH_LAST_FILE"`
+		}
+
+		resetSearchEnv := func() {
+			_ = os.Unsetenv("SEARCH_FIRST")
+			_ = os.Unsetenv("SEARCH_SECOND")
+			_ = os.Unsetenv("SEARCH_CUSTOM")
+			_ = os.Unsetenv("SEARCH_LAST_FILE")
+		}
+
+		firstPath := filepath.Join("testdata", "search", "first")
+		secondPath := filepath.Join("testdata", "search", "second")
+
+		t.Run("should load files from first matching search path", func(t *testing.T) {
+			resetSearchEnv()
+			defer resetSearchEnv()
+
+			c := searchConfig{}
+			configLoader := NewConfigLoader().
+				UseSearchPaths(firstPath, secondPath).
+				UseEnvironment(Develop).
+				UseDefaultLoadBehavior()
+			err := configLoader.LoadInto(&c)
+
+			expectedConfig := searchConfig{
+				First:    true,
+				Custom:   true,
+				LastFile: "first-develop",
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, expectedConfig, c)
+			assert.Equal(t, []string{
+				filepath.Join(firstPath, "cfg.dev.env"),
+				filepath.Join(secondPath, ".env"),
+			}, configLoader.ResolvedPaths())
+		})
+
+		t.Run("should merge files from all search paths", func(t *testing.T) {
+			resetSearchEnv()
+			defer resetSearchEnv()
+
+			c := searchConfig{}
+			configLoader := NewConfigLoader().
+				UseSearchPaths(firstPath, secondPath).
+				UseSearchMode(SearchModeMergeAll).
+				UseEnvironment(Develop).
+				UseDefaultLoadBehavior()
+			err := configLoader.LoadInto(&c)
+
+			expectedConfig := searchConfig{
+				First:    true,
+				Second:   true,
+				Custom:   true,
+				LastFile: "first-develop",
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, expectedConfig, c)
+			assert.Equal(t, []string{
+				filepath.Join(secondPath, "cfg.dev.env"),
+				filepath.Join(firstPath, "cfg.dev.env"),
+				filepath.Join(secondPath, ".env"),
+			}, configLoader.ResolvedPaths())
+		})
+	})
+
+	t.Run("file format detection", func(t *testing.T) {
+		t.Run("should load files with different formats", func(t *testing.T) {
+			resetEnv()
+
+			c := testConfig{}
+			err := NewConfigLoader().
+				UseLoadPath("./testdata/detect").
+				UseEnvironment(Production).
+				UseFileFormatDetection().
+				UseDefaultLoadBehavior().
+				LoadInto(&c)
+
+			// dotenv values are written into the OS environment and take precedence over other formats
+			expectedConfig := testConfig{
+				Production: true,
+				Custom:     true,
+				LastFile:   "prod",
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, expectedConfig, c)
+		})
+
+		t.Run("should only consider extensions of priority order", func(t *testing.T) {
+			resetEnv()
+
+			c := testConfig{}
+			err := NewConfigLoader().
+				UseLoadPath("./testdata").
+				UseEnvironment(Develop).
+				UseFileFormatDetection(YAML).
+				UseDefaultLoadBehavior().
+				LoadInto(&c)
+
+			assert.NoError(t, err)
+			assert.True(t, c.Develop)
+		})
+
+		t.Run("should return error when file exists in multiple formats", func(t *testing.T) {
+			resetEnv()
+
+			c := testConfig{}
+			err := NewConfigLoader().
+				UseLoadPath("./testdata").
+				UseEnvironment(Develop).
+				UseFileFormatDetection().
+				UseDefaultLoadBehavior().
+				LoadInto(&c)
+
+			var configFileErr *ConfigFileError
+			require.True(t, errors.As(err, &configFileErr))
+			assert.Equal(t, filepath.Join("testdata", "cfg.dev"), configFileErr.Path)
+			assert.True(t, errors.Is(err, ErrAmbiguousConfigFile))
+		})
+	})
+
+	t.Run("required files", func(t *testing.T) {
+		t.Run("should return error when required file of current environment is missing", func(t *testing.T) {
+			resetEnv()
+
+			c := testConfig{}
+			err := NewConfigLoader().
+				UseLoadPath("./testdata").
+				UseFileProcessor(YAML).
+				UseEnvironment(Production).
+				RequireFileForEnvironment(Production).
+				UseDefaultLoadBehavior().
+				LoadInto(&c)
+
+			var configFileErr *ConfigFileError
+			require.True(t, errors.As(err, &configFileErr))
+			assert.Equal(t, filepath.Join("testdata", "cfg.prod.yaml"), configFileErr.Path)
+			assert.True(t, errors.Is(err, ErrConfigFileNotFound))
+		})
+
+		t.Run("should not return error when required file of other environment is missing", func(t *testing.T) {
+			resetEnv()
+
+			c := testConfig{}
+			err := NewConfigLoader().
+				UseLoadPath("./testdata").
+				UseFileProcessor(YAML).
+				UseEnvironment(Develop).
+				RequireFileForEnvironment(Production).
+				UseDefaultLoadBehavior().
+				LoadInto(&c)
+
+			assert.NoError(t, err)
+			assert.True(t, c.Develop)
+		})
+
+		t.Run("should return error when required file is missing", func(t *testing.T) {
+			resetEnv()
+
+			c := testConfig{}
+			err := NewConfigLoader().
+				UseCustomLoadBehavior().
+				LoadFromFile("./testdata/missing.env").
+				LoadFromRequiredFile("./testdata/missing-required.env").
+				LoadInto(&c)
+
+			var configFileErr *ConfigFileError
+			require.True(t, errors.As(err, &configFileErr))
+			assert.Equal(t, "./testdata/missing-required.env", configFileErr.Path)
+			assert.True(t, errors.Is(err, ErrConfigFileNotFound))
+		})
+	})
+
 	t.Run("custom behavior", func(t *testing.T) {
 		t.Run("should load a specific file without condition", func(t *testing.T) {
 			resetEnv()
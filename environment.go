@@ -0,0 +1,113 @@
+package yetenv
+
+// environmentRanks defines the ordering of the built-in environments which is used by IsAtLeast.
+var environmentRanks = map[Environment]int{
+	Develop:    1,
+	Test:       2,
+	Staging:    3,
+	Production: 4,
+}
+
+// ParseEnvironment parses a value like a value of the environment variable. It returns an *EnvironmentError
+// instead of falling back to Develop when the value is unknown.
+func ParseEnvironment(value string) (Environment, error) {
+	if normalizeVariableValue(value) == string(Custom) {
+		return Custom, nil
+	}
+
+	environment, ok := lookupEnvironment(value)
+	if !ok {
+		return "", &EnvironmentError{Value: value, Err: ErrUnknownEnvironmentValue}
+	}
+
+	return environment, nil
+}
+
+// String returns the string value of the Environment.
+func (e Environment) String() string {
+	return string(e)
+}
+
+// IsProduction returns true when the Environment is Production.
+func (e Environment) IsProduction() bool {
+	return e == Production
+}
+
+// IsStaging returns true when the Environment is Staging.
+func (e Environment) IsStaging() bool {
+	return e == Staging
+}
+
+// IsTest returns true when the Environment is Test.
+func (e Environment) IsTest() bool {
+	return e == Test
+}
+
+// IsDevelop returns true when the Environment is Develop.
+func (e Environment) IsDevelop() bool {
+	return e == Develop
+}
+
+// IsCustom returns true when the Environment is Custom.
+func (e Environment) IsCustom() bool {
+	return e == Custom
+}
+
+// IsAtLeast returns true when the Environment is ranked equal or higher than the provided one.
+// The order is Develop < Test < Staging < Production. Registered environments are ranked like the environment
+// defined by EnvironmentRegistration.RanksLike. Unranked environments (e.g. Custom) are never at least another one.
+func (e Environment) IsAtLeast(other Environment) bool {
+	rank, ok := e.rank()
+	if !ok {
+		return false
+	}
+
+	otherRank, ok := other.rank()
+	if !ok {
+		return false
+	}
+
+	return rank >= otherRank
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e Environment) MarshalText() ([]byte, error) {
+	return []byte(e), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by using ParseEnvironment.
+// An empty text is unmarshaled into the zero value, so it round-trips with MarshalText.
+func (e *Environment) UnmarshalText(text []byte) error {
+	return e.SetValue(string(text))
+}
+
+// SetValue implements the setter of cleanenv, so Environment fields can be read from environment variables.
+// An empty value sets the zero value.
+func (e *Environment) SetValue(value string) error {
+	if normalizeVariableValue(value) == "" {
+		*e = ""
+		return nil
+	}
+
+	environment, err := ParseEnvironment(value)
+	if err != nil {
+		return err
+	}
+
+	*e = environment
+	return nil
+}
+
+func (e Environment) rank() (int, bool) {
+	if rank, ok := environmentRanks[e]; ok {
+		return rank, true
+	}
+
+	ranksLike, ok := registeredEnvironmentRanksLike(e)
+	if !ok {
+		return 0, false
+	}
+
+	rank, ok := environmentRanks[ranksLike]
+	return rank, ok
+}
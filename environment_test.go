@@ -0,0 +1,128 @@
+package yetenv
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvironment(t *testing.T) {
+	t.Run("should parse known values", func(t *testing.T) {
+		expectations := map[string]Environment{
+			"production": Production,
+			"STG":        Staging,
+			"test":       Test,
+			"dev":        Develop,
+			"custom":     Custom,
+		}
+
+		for value, expectedEnv := range expectations {
+			actualEnv, err := ParseEnvironment(value)
+			assert.NoError(t, err)
+			assert.Equal(t, expectedEnv, actualEnv)
+		}
+	})
+
+	t.Run("should return error for unknown values", func(t *testing.T) {
+		_, err := ParseEnvironment("prodution")
+		assert.True(t, errors.Is(err, ErrUnknownEnvironmentValue))
+		assert.Equal(t, "unknown environment 'prodution'", err.Error())
+	})
+}
+
+func TestEnvironment_Predicates(t *testing.T) {
+	assert.True(t, Production.IsProduction())
+	assert.False(t, Staging.IsProduction())
+	assert.True(t, Staging.IsStaging())
+	assert.True(t, Test.IsTest())
+	assert.True(t, Develop.IsDevelop())
+	assert.True(t, Custom.IsCustom())
+	assert.Equal(t, "production", Production.String())
+}
+
+func TestEnvironment_IsAtLeast(t *testing.T) {
+	t.Run("should order built-in environments", func(t *testing.T) {
+		assert.True(t, Production.IsAtLeast(Staging))
+		assert.True(t, Staging.IsAtLeast(Staging))
+		assert.True(t, Test.IsAtLeast(Develop))
+		assert.False(t, Develop.IsAtLeast(Test))
+		assert.False(t, Custom.IsAtLeast(Develop))
+		assert.False(t, Production.IsAtLeast(Custom))
+	})
+
+	t.Run("should rank registered environments", func(t *testing.T) {
+		defer UnregisterEnvironment("qa")
+		defer UnregisterEnvironment("sandbox")
+
+		require.NoError(t, RegisterEnvironment(EnvironmentRegistration{Environment: "qa", RanksLike: Staging}))
+		require.NoError(t, RegisterEnvironment(EnvironmentRegistration{Environment: "sandbox"}))
+
+		assert.True(t, Environment("qa").IsAtLeast(Staging))
+		assert.False(t, Environment("qa").IsAtLeast(Production))
+		assert.True(t, Production.IsAtLeast("qa"))
+		assert.False(t, Environment("sandbox").IsAtLeast(Develop))
+	})
+}
+
+func TestEnvironment_TextMarshaling(t *testing.T) {
+	type jsonConfig struct {
+		Environment Environment `json:"environment"`
+	}
+
+	data, err := json.Marshal(jsonConfig{Environment: Staging})
+	require.NoError(t, err)
+	assert.Equal(t, `{"environment":"staging"}`, string(data))
+
+	c := jsonConfig{}
+	err = json.Unmarshal([]byte(`{"environment":"PRD"}`), &c)
+	assert.NoError(t, err)
+	assert.Equal(t, Production, c.Environment)
+
+	err = json.Unmarshal([]byte(`{"environment":"prodution"}`), &c)
+	assert.Error(t, err)
+
+	t.Run("should round-trip the zero value", func(t *testing.T) {
+		data, err := json.Marshal(jsonConfig{})
+		require.NoError(t, err)
+
+		c := jsonConfig{Environment: Production}
+		err = json.Unmarshal(data, &c)
+		assert.NoError(t, err)
+		assert.Equal(t, Environment(""), c.Environment)
+	})
+}
+
+func TestConfigLoader_LoadInto_EnvironmentField(t *testing.T) {
+	type environmentConfig struct {
+		Environment Environment `yaml:"environment" env:"CONFIG_ENVIRONMENT"`
+	}
+
+	t.Run("should load environment field from file", func(t *testing.T) {
+		c := environmentConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			LoadFromFile("./testdata/environment.yaml").
+			LoadInto(&c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Production, c.Environment)
+	})
+
+	t.Run("should load environment field from environment variable", func(t *testing.T) {
+		require.NoError(t, os.Setenv("CONFIG_ENVIRONMENT", "stg"))
+		defer func() { _ = os.Unsetenv("CONFIG_ENVIRONMENT") }()
+
+		c := environmentConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			LoadFromFile("./testdata/environment.yaml").
+			LoadInto(&c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Staging, c.Environment)
+	})
+}
@@ -0,0 +1,207 @@
+package yetenv
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// structField is a leaf field of a config struct. Nested structs (except time.Time) are flattened into paths
+// like 'Database.Host'. Non-nil pointers to structs are followed, and slices, arrays and maps of structs are walked
+// element by element (e.g. 'Replicas[0].Host' or 'ByName[primary].Host').
+type structField struct {
+	path        string
+	segments    []pathSegment
+	envNames    []string
+	secret      bool
+	structField reflect.StructField
+	value       reflect.Value
+	// composite marks the slice, array or map field itself, whose struct elements are collected as separate fields.
+	composite bool
+	// mapEntry is set for fields of a map element, because map elements can only be changed by storing them again.
+	mapEntry *mapEntry
+}
+
+// pathSegment is a part of the path of a structField. Segments with an index are elements of slices and arrays.
+type pathSegment struct {
+	name   string
+	index  int
+	mapKey bool
+}
+
+type mapEntry struct {
+	mapValue reflect.Value
+	key      reflect.Value
+	value    reflect.Value
+}
+
+// store writes the (changed) copy of a map element back into the map.
+func (e *mapEntry) store() {
+	if e != nil {
+		e.mapValue.SetMapIndex(e.key, e.value)
+	}
+}
+
+func collectStructFields(cfg interface{}) []structField {
+	value := reflect.ValueOf(cfg)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := []structField{}
+	collectNestedStructFields(value, nil, false, true, nil, &fields)
+
+	return fields
+}
+
+func collectNestedStructFields(value reflect.Value, segments []pathSegment, secret bool, withEnvNames bool, entry *mapEntry, fields *[]structField) {
+	valueType := value.Type()
+
+	for i := 0; i < value.NumField(); i++ {
+		fieldType := valueType.Field(i)
+		if fieldType.PkgPath != "" {
+			continue
+		}
+
+		fieldSegments := appendPathSegment(segments, pathSegment{name: fieldType.Name, index: -1})
+		fieldSecret := secret || isSecretField(fieldType)
+
+		var envNames []string
+		if envTag, ok := fieldType.Tag.Lookup("env"); ok && envTag != "" && withEnvNames {
+			envNames = strings.Split(envTag, ",")
+		}
+
+		field := structField{
+			segments:    fieldSegments,
+			envNames:    envNames,
+			secret:      fieldSecret,
+			structField: fieldType,
+			value:       value.Field(i),
+			mapEntry:    entry,
+		}
+
+		collectFieldValue(field, withEnvNames, fields)
+	}
+}
+
+func collectFieldValue(field structField, withEnvNames bool, fields *[]structField) {
+	value := field.value
+	for value.Kind() == reflect.Ptr && !value.IsNil() && hasStructElements(value.Type()) {
+		value = value.Elem()
+	}
+
+	switch {
+	case value.Kind() == reflect.Struct && value.Type() != timeType:
+		collectNestedStructFields(value, field.segments, field.secret, withEnvNames, field.mapEntry, fields)
+		return
+	case (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) && hasStructElements(value.Type().Elem()):
+		field.composite = true
+		appendStructField(field, fields)
+
+		for i := 0; i < value.Len(); i++ {
+			element := field
+			element.segments = appendPathSegment(field.segments, pathSegment{index: i})
+			element.value = value.Index(i)
+			element.envNames = nil
+			element.composite = false
+			collectFieldValue(element, false, fields)
+		}
+		return
+	case value.Kind() == reflect.Map && hasStructElements(value.Type().Elem()):
+		field.composite = true
+		appendStructField(field, fields)
+
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		for _, key := range keys {
+			elementValue := reflect.New(value.Type().Elem()).Elem()
+			elementValue.Set(value.MapIndex(key))
+
+			element := field
+			element.segments = appendPathSegment(field.segments, pathSegment{name: fmt.Sprint(key.Interface()), index: -1, mapKey: true})
+			element.value = elementValue
+			element.envNames = nil
+			element.composite = false
+			element.mapEntry = &mapEntry{mapValue: value, key: key, value: elementValue}
+			collectFieldValue(element, false, fields)
+		}
+		return
+	}
+
+	appendStructField(field, fields)
+}
+
+func appendStructField(field structField, fields *[]structField) {
+	field.path = formatPath(field.segments)
+	*fields = append(*fields, field)
+}
+
+// hasStructElements reports whether a type is a struct (except time.Time) or a pointer to one.
+func hasStructElements(valueType reflect.Type) bool {
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+
+	return valueType.Kind() == reflect.Struct && valueType != timeType
+}
+
+func appendPathSegment(segments []pathSegment, segment pathSegment) []pathSegment {
+	return append(append([]pathSegment{}, segments...), segment)
+}
+
+func formatPath(segments []pathSegment) string {
+	var builder strings.Builder
+	for i, segment := range segments {
+		switch {
+		case segment.index >= 0:
+			builder.WriteString("[" + strconv.Itoa(segment.index) + "]")
+		case segment.mapKey:
+			builder.WriteString("[" + segment.name + "]")
+		default:
+			if i > 0 {
+				builder.WriteString(".")
+			}
+			builder.WriteString(segment.name)
+		}
+	}
+
+	return builder.String()
+}
+
+// isWalkableType reports whether a value of the type can be printed without hiding secret fields, which is the case
+// when it does not contain structs (except time.Time), interfaces, channels or functions.
+func isWalkableType(valueType reflect.Type) bool {
+	switch valueType.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return isWalkableType(valueType.Elem())
+	case reflect.Map:
+		return isWalkableType(valueType.Key()) && isWalkableType(valueType.Elem())
+	case reflect.Struct:
+		return valueType == timeType
+	case reflect.Interface, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return false
+	}
+
+	return true
+}
+
+func isSecretField(fieldType reflect.StructField) bool {
+	secret, err := strconv.ParseBool(fieldType.Tag.Get("secret"))
+	return err == nil && secret
+}
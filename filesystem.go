@@ -0,0 +1,173 @@
+package yetenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v2"
+)
+
+// UseFileSystem can be used to load config files from an fs.FS (e.g. embed.FS or fstest.MapFS) instead of the
+// OS file system. All paths (load path, search paths and paths of LoadFromFile) are resolved against the fs.FS.
+func (c *ConfigLoader) UseFileSystem(fileSystem fs.FS) *ConfigLoader {
+	c.FileSystem = fileSystem
+	return c
+}
+
+func (c *ConfigLoader) fileExists(filename string) bool {
+	if c.FileSystem == nil {
+		return fileExists(filename)
+	}
+
+	info, err := fs.Stat(c.FileSystem, fileSystemPath(filename))
+	if err != nil {
+		return false
+	}
+
+	return !info.IsDir()
+}
+
+func (c *ConfigLoader) readConfigFile(filename string, cfg interface{}) error {
+	var file io.ReadCloser
+	var err error
+	if c.FileSystem == nil {
+		file, err = os.Open(filename)
+	} else {
+		file, err = c.FileSystem.Open(fileSystemPath(filename))
+	}
+
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fileExtension := ConfigFileExtension(strings.ToLower(filepath.Ext(filename)))
+	return c.readConfig(file, fileExtension, FieldSource{Type: SourceFile, Name: filename}, cfg)
+}
+
+// readConfig parses a config and reads the OS environment afterwards like cleanenv.ReadConfig does.
+// The changed fields of both phases are recorded by the provenance tracker.
+func (c *ConfigLoader) readConfig(r io.Reader, fileExtension ConfigFileExtension, source FieldSource, cfg interface{}) error {
+	c.provenance.beginStep(cfg)
+
+	var dotenvVariables map[string]string
+	var err error
+	if c.Interpolation {
+		dotenvVariables, err = c.parseInterpolatedConfig(r, fileExtension, cfg)
+	} else {
+		dotenvVariables, err = parseConfig(r, fileExtension, cfg)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if c.pendingDotenvVariables != nil {
+		for name, value := range dotenvVariables {
+			c.pendingDotenvVariables[name] = value
+		}
+
+		return nil
+	}
+
+	if err := c.setDotenvVariables(dotenvVariables); err != nil {
+		return err
+	}
+
+	c.provenance.recordParsedSource(cfg, source, dotenvVariables)
+
+	if err := cleanenv.ReadEnv(cfg); err != nil {
+		return err
+	}
+
+	c.provenance.recordEnvVariables(cfg)
+	return nil
+}
+
+// parseConfig parses a config from a reader the same way cleanenv parses config files.
+// The variables of dotenv files are returned, because they are written into the OS environment.
+func parseConfig(r io.Reader, fileExtension ConfigFileExtension, cfg interface{}) (map[string]string, error) {
+	var dotenvVariables map[string]string
+	var err error
+	switch fileExtension {
+	case YAML, ".yml":
+		err = yaml.NewDecoder(r).Decode(cfg)
+	case JSON:
+		err = json.NewDecoder(r).Decode(cfg)
+	case TOML:
+		_, err = toml.DecodeReader(r, cfg)
+	case DOTENV:
+		dotenvVariables, err = parseDotenv(r)
+	default:
+		return nil, fmt.Errorf("file format '%s' doesn't supported by the parser", fileExtension)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("config file parsing error: %s", err.Error())
+	}
+
+	return dotenvVariables, nil
+}
+
+func parseDotenv(r io.Reader) (map[string]string, error) {
+	return godotenv.Parse(r)
+}
+
+// setDotenvVariables writes the variables of a dotenv file into the OS environment like cleanenv does.
+// The previous values are remembered, so a Watcher can restore them when a variable is removed from the dotenv files.
+func (c *ConfigLoader) setDotenvVariables(variables map[string]string) error {
+	if c.dotenvOriginals == nil {
+		c.dotenvOriginals = map[string]*string{}
+	}
+
+	for name, value := range variables {
+		if _, ok := c.dotenvOriginals[name]; !ok {
+			var original *string
+			if originalValue, ok := os.LookupEnv(name); ok {
+				original = &originalValue
+			}
+
+			c.dotenvOriginals[name] = original
+		}
+
+		if err := os.Setenv(name, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lookupEnv looks up a variable of the OS environment. While the variables of dotenv files are collected, the
+// collected variables are used instead of the ones which have been set by previous loads.
+func (c *ConfigLoader) lookupEnv(name string) (string, bool) {
+	if c.pendingDotenvVariables != nil {
+		if value, ok := c.pendingDotenvVariables[name]; ok {
+			return value, true
+		}
+
+		if original, ok := c.dotenvOriginals[name]; ok {
+			if original == nil {
+				return "", false
+			}
+
+			return *original, true
+		}
+	}
+
+	return os.LookupEnv(name)
+}
+
+func fileSystemPath(filename string) string {
+	cleanedPath := path.Clean(filepath.ToSlash(filename))
+	return strings.TrimPrefix(cleanedPath, "/")
+}
@@ -0,0 +1,106 @@
+package yetenv
+
+import (
+	"embed"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata testdata/.env
+var embeddedTestdata embed.FS
+
+func TestConfigLoader_UseFileSystem(t *testing.T) {
+	t.Run("should load default behavior from map file system", func(t *testing.T) {
+		resetEnv()
+
+		fileSystem := fstest.MapFS{
+			"config/cfg.staging.yaml": {Data: []byte("staging: true\nlast_file: \"staging\"")},
+			"config/cfg.yaml":         {Data: []byte("custom: true\nlast_file: \"custom\"")},
+			"config/cfg.prod.yaml":    {Data: []byte("prod: true\nlast_file: \"prod\"")},
+		}
+
+		c := testConfig{}
+		err := NewConfigLoader().
+			UseFileSystem(fileSystem).
+			UseLoadPath("./config").
+			UseFileProcessor(YAML).
+			UseEnvironment(Staging).
+			UseDefaultLoadBehavior().
+			LoadInto(&c)
+
+		expectedConfig := testConfig{
+			Staging:  true,
+			Custom:   true,
+			LastFile: "custom",
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedConfig, c)
+	})
+
+	t.Run("should load dotenv files from embedded file system", func(t *testing.T) {
+		resetEnv()
+
+		c := testConfig{}
+		err := NewConfigLoader().
+			UseFileSystem(embeddedTestdata).
+			UseLoadPath("testdata").
+			UseEnvironment(Production).
+			UseDefaultLoadBehavior().
+			LoadInto(&c)
+
+		expectedConfig := testConfig{
+			Production: true,
+			Custom:     true,
+			LastFile:   "custom",
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedConfig, c)
+	})
+
+	t.Run("should load specific file and report missing required file", func(t *testing.T) {
+		resetEnv()
+
+		fileSystem := fstest.MapFS{
+			"custom.json": {Data: []byte(`{"Custom": true, "LastFile": "json"}`)},
+		}
+
+		c := testConfig{}
+		err := NewConfigLoader().
+			UseFileSystem(fileSystem).
+			UseCustomLoadBehavior().
+			LoadFromFile("./custom.json").
+			LoadInto(&c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, testConfig{Custom: true, LastFile: "json"}, c)
+
+		err = NewConfigLoader().
+			UseFileSystem(fileSystem).
+			UseCustomLoadBehavior().
+			LoadFromRequiredFile("missing.json").
+			LoadInto(&c)
+
+		assert.True(t, errors.Is(err, ErrConfigFileNotFound))
+	})
+
+	t.Run("should return error for unsupported file format", func(t *testing.T) {
+		fileSystem := fstest.MapFS{
+			"cfg.ini": {Data: []byte("custom=true")},
+		}
+
+		c := testConfig{}
+		err := NewConfigLoader().
+			UseFileSystem(fileSystem).
+			UseCustomLoadBehavior().
+			LoadFromFile("cfg.ini").
+			LoadInto(&c)
+
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,50 @@
+package yetenv
+
+import (
+	"flag"
+)
+
+// DefaultFlagName defines the default name of the environment command-line flag.
+var DefaultFlagName = "env"
+
+var _ flag.Value = (*Environment)(nil)
+
+// Set implements flag.Value by using ParseEnvironment, so an Environment can be used with flag.Var.
+func (e *Environment) Set(value string) error {
+	return e.SetValue(value)
+}
+
+// UseEnvironmentFlag registers an environment flag (e.g. '-env=staging') on the provided flag set. When the flag is
+// passed on the command line, the parsed Environment is injected by UseEnvironment after parsing the flag set.
+// This results in the precedence: flag, then environment variable, then default.
+// If name is empty, DefaultFlagName is used.
+func (c *ConfigLoader) UseEnvironmentFlag(flagSet *flag.FlagSet, name string) *ConfigLoader {
+	if name == "" {
+		name = DefaultFlagName
+	}
+
+	flagSet.Var(&environmentFlag{configLoader: c}, name, "overrides the environment (e.g. develop, test, staging, production)")
+	return c
+}
+
+type environmentFlag struct {
+	configLoader *ConfigLoader
+}
+
+func (f *environmentFlag) String() string {
+	if f.configLoader == nil {
+		return ""
+	}
+
+	return string(f.configLoader.Environment)
+}
+
+func (f *environmentFlag) Set(value string) error {
+	environment, err := ParseEnvironment(value)
+	if err != nil {
+		return err
+	}
+
+	f.configLoader.UseEnvironment(environment)
+	return nil
+}
@@ -0,0 +1,75 @@
+package yetenv
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironment_Set(t *testing.T) {
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	flagSet.SetOutput(ioutil.Discard)
+
+	environment := Develop
+	flagSet.Var(&environment, "env", "environment")
+
+	err := flagSet.Parse([]string{"-env=stg"})
+	assert.NoError(t, err)
+	assert.Equal(t, Staging, environment)
+
+	err = flagSet.Parse([]string{"-env=prodution"})
+	assert.Error(t, err)
+}
+
+func TestConfigLoader_UseEnvironmentFlag(t *testing.T) {
+	previousVariableName := DefaultVariableName
+	defer func() {
+		DefaultVariableName = previousVariableName
+		_ = os.Unsetenv("FLAG_TEST_ENV")
+	}()
+
+	DefaultVariableName = "FLAG_TEST_ENV"
+	require.NoError(t, os.Setenv("FLAG_TEST_ENV", "staging"))
+
+	t.Run("should prefer flag over environment variable", func(t *testing.T) {
+		resetEnv()
+
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		configLoader := NewConfigLoader().
+			UseLoadPath("./testdata").
+			UseEnvironmentFlag(flagSet, "").
+			UseDefaultLoadBehavior()
+
+		require.NoError(t, flagSet.Parse([]string{"-env=production"}))
+
+		c := testConfig{}
+		err := configLoader.LoadInto(&c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Production, configLoader.Environment)
+		assert.True(t, c.Production)
+	})
+
+	t.Run("should use environment variable when flag is not set", func(t *testing.T) {
+		resetEnv()
+
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		configLoader := NewConfigLoader().
+			UseLoadPath("./testdata").
+			UseEnvironmentFlag(flagSet, "environment").
+			UseDefaultLoadBehavior()
+
+		require.NoError(t, flagSet.Parse([]string{}))
+
+		c := testConfig{}
+		err := configLoader.LoadInto(&c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Staging, configLoader.Environment)
+		assert.True(t, c.Staging)
+	})
+}
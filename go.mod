@@ -1,8 +1,16 @@
 module github.com/pvormste/yetenv
 
-go 1.13
+go 1.18
 
 require (
+	github.com/BurntSushi/toml v0.3.1
 	github.com/ilyakaznacheev/cleanenv v1.2.1
+	github.com/joho/godotenv v1.3.0
 	github.com/stretchr/testify v1.5.1
+	gopkg.in/yaml.v2 v2.2.2
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 )
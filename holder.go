@@ -0,0 +1,101 @@
+package yetenv
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// ConfigHolder stores a loaded config of type T behind an atomic value, so it can be read by many goroutines while it
+// is replaced by a reload. A stored config is never changed afterwards, so readers never see a half-applied load.
+type ConfigHolder[T any] struct {
+	value       atomic.Value
+	mutex       sync.Mutex
+	subscribers map[chan *T]struct{}
+}
+
+// NewConfigHolder creates a ConfigHolder for the config struct T. The provided config is the initial value of the
+// ConfigHolder and must not be nil.
+func NewConfigHolder[T any](cfg *T) (*ConfigHolder[T], error) {
+	if cfg == nil || reflect.TypeOf(cfg).Elem().Kind() != reflect.Struct {
+		return nil, ErrNoConfigStruct
+	}
+
+	holder := &ConfigHolder[T]{
+		subscribers: map[chan *T]struct{}{},
+	}
+	holder.value.Store(cfg)
+
+	return holder, nil
+}
+
+// Get returns a pointer to the current config. The config must not be changed, because it is shared by all readers.
+func (h *ConfigHolder[T]) Get() *T {
+	return h.value.Load().(*T)
+}
+
+// Set replaces the current config and notifies all subscribers. The config must not be nil and must not be changed
+// afterwards.
+func (h *ConfigHolder[T]) Set(cfg *T) error {
+	if cfg == nil {
+		return ErrNoConfigStruct
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.value.Store(cfg)
+
+	for subscriber := range h.subscribers {
+		// Subscribers only receive the latest config, so a stale config is dropped for a slow subscriber.
+		select {
+		case <-subscriber:
+		default:
+		}
+
+		subscriber <- cfg
+	}
+
+	return nil
+}
+
+// Subscribe returns a channel which receives every config stored by Set. A slow subscriber only receives the latest
+// config. The returned function unsubscribes and closes the channel.
+func (h *ConfigHolder[T]) Subscribe() (<-chan *T, func()) {
+	subscriber := make(chan *T, 1)
+
+	h.mutex.Lock()
+	h.subscribers[subscriber] = struct{}{}
+	h.mutex.Unlock()
+
+	var unsubscribeOnce sync.Once
+	unsubscribe := func() {
+		unsubscribeOnce.Do(func() {
+			h.mutex.Lock()
+			defer h.mutex.Unlock()
+
+			delete(h.subscribers, subscriber)
+			close(subscriber)
+		})
+	}
+
+	return subscriber, unsubscribe
+}
+
+// LoadIntoHolder will finish the ConfigLoader like LoadInto, but loads into a new config struct which is stored in the
+// ConfigHolder afterwards. The ConfigHolder keeps its current config when loading fails.
+func LoadIntoHolder[T any](configLoader *ConfigLoader, holder *ConfigHolder[T]) error {
+	cfg := new(T)
+	if err := configLoader.LoadInto(cfg); err != nil {
+		return err
+	}
+
+	return holder.Set(cfg)
+}
+
+// WatchHolder works like Watch of the ConfigLoader, but every loaded config is stored in the ConfigHolder.
+func WatchHolder[T any](configLoader *ConfigLoader, holder *ConfigHolder[T]) (*Watcher, error) {
+	return configLoader.watch(new(T), func(cfg interface{}) {
+		_ = holder.Set(cfg.(*T))
+	})
+}
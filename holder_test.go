@@ -0,0 +1,148 @@
+package yetenv
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type holderTestConfig struct {
+	Name  string `yaml:"name"`
+	Count int    `yaml:"count"`
+}
+
+func TestNewConfigHolder(t *testing.T) {
+	t.Run("should return error for nil and non struct configs", func(t *testing.T) {
+		_, err := NewConfigHolder((*holderTestConfig)(nil))
+		assert.Equal(t, ErrNoConfigStruct, err)
+
+		name := "name"
+		_, err = NewConfigHolder(&name)
+		assert.Equal(t, ErrNoConfigStruct, err)
+	})
+
+	t.Run("should store initial config", func(t *testing.T) {
+		initial := &holderTestConfig{Name: "initial"}
+		holder, err := NewConfigHolder(initial)
+		require.NoError(t, err)
+
+		assert.Same(t, initial, holder.Get())
+	})
+}
+
+func TestConfigHolder_Set(t *testing.T) {
+	holder, err := NewConfigHolder(&holderTestConfig{})
+	require.NoError(t, err)
+
+	t.Run("should return error for nil config", func(t *testing.T) {
+		assert.Equal(t, ErrNoConfigStruct, holder.Set(nil))
+	})
+
+	t.Run("should replace config", func(t *testing.T) {
+		cfg := &holderTestConfig{Name: "replaced"}
+		require.NoError(t, holder.Set(cfg))
+
+		assert.Same(t, cfg, holder.Get())
+	})
+
+	t.Run("should be safe for concurrent readers", func(t *testing.T) {
+		wg := sync.WaitGroup{}
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					cfg := holder.Get()
+					assert.Equal(t, cfg.Count, len(cfg.Name))
+				}
+			}()
+		}
+
+		name := ""
+		for i := 0; i < 100; i++ {
+			name += "x"
+			require.NoError(t, holder.Set(&holderTestConfig{Name: name, Count: len(name)}))
+		}
+
+		wg.Wait()
+	})
+}
+
+func TestConfigHolder_Subscribe(t *testing.T) {
+	holder, err := NewConfigHolder(&holderTestConfig{})
+	require.NoError(t, err)
+
+	updates, unsubscribe := holder.Subscribe()
+
+	t.Run("should only receive latest config", func(t *testing.T) {
+		require.NoError(t, holder.Set(&holderTestConfig{Name: "first"}))
+		require.NoError(t, holder.Set(&holderTestConfig{Name: "second"}))
+
+		assert.Equal(t, &holderTestConfig{Name: "second"}, <-updates)
+		assert.Len(t, updates, 0)
+	})
+
+	t.Run("should close channel on unsubscribe", func(t *testing.T) {
+		unsubscribe()
+		unsubscribe()
+
+		_, ok := <-updates
+		assert.False(t, ok)
+		assert.NoError(t, holder.Set(&holderTestConfig{Name: "third"}))
+	})
+}
+
+func TestLoadIntoHolder(t *testing.T) {
+	holder, err := NewConfigHolder(&holderTestConfig{})
+	require.NoError(t, err)
+
+	t.Run("should store loaded config", func(t *testing.T) {
+		configLoader := NewConfigLoader().
+			UseCustomLoadBehavior().
+			LoadFromBytes([]byte("name: loaded"), YAML)
+
+		err := LoadIntoHolder(configLoader, holder)
+		require.NoError(t, err)
+
+		assert.Equal(t, &holderTestConfig{Name: "loaded"}, holder.Get())
+	})
+
+	t.Run("should keep config when loading fails", func(t *testing.T) {
+		configLoader := NewConfigLoader().
+			UseCustomLoadBehavior().
+			LoadFromBytes([]byte("name: [loaded"), YAML)
+
+		err := LoadIntoHolder(configLoader, holder)
+		assert.Error(t, err)
+
+		assert.Equal(t, &holderTestConfig{Name: "loaded"}, holder.Get())
+	})
+}
+
+func TestWatchHolder(t *testing.T) {
+	holder, err := NewConfigHolder(&holderTestConfig{})
+	require.NoError(t, err)
+
+	updates, unsubscribe := holder.Subscribe()
+	defer unsubscribe()
+
+	configLoader := NewConfigLoader().
+		UseCustomLoadBehavior().
+		UseWatchInterval(time.Hour).
+		LoadFromFile(filepath.Join("testdata", "provenance.yaml"))
+
+	watcher, err := WatchHolder(configLoader, holder)
+	require.NoError(t, err)
+	defer watcher.Stop()
+
+	assert.Same(t, watcher.Config(), <-updates)
+
+	require.NoError(t, watcher.Reload())
+
+	assert.Same(t, watcher.Config(), <-updates)
+	assert.Same(t, watcher.Config(), holder.Get())
+}
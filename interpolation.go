@@ -0,0 +1,477 @@
+package yetenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v2"
+)
+
+// dotenvDollarPlaceholder hides '$' from godotenv, which would expand variables on its own.
+const dotenvDollarPlaceholder = "\x00"
+
+var (
+	ErrInterpolationCycle     = errors.New("variable reference cycle")
+	ErrRequiredVariableNotSet = errors.New("required variable is not set")
+	ErrInvalidVariableSyntax  = errors.New("invalid variable reference")
+)
+
+// UseInterpolation enables the interpolation of variable references in config files, readers and raw bytes.
+// The following references are supported ('$$' is replaced by a literal '$'):
+//   - ${NAME}: the value of NAME or an empty string
+//   - ${NAME:-default} / ${NAME-default}: the default when NAME is unset or empty / unset
+//   - ${NAME:?message} / ${NAME?message}: an error when NAME is unset or empty / unset
+//
+// NAME is looked up in the keys of the same dotenv file, the OS environment and the keys of earlier load items
+// (nested keys of YAML, JSON and TOML are joined by '.', e.g. 'database.host').
+//
+// YAML, JSON and TOML are parsed before the interpolation, so only their string values are interpolated and comments
+// are ignored. Interpolated YAML values which are plain numbers or booleans keep their type, interpolated JSON and
+// TOML values are strings. Dotenv files additionally support '$NAME' without braces, like godotenv does without
+// interpolation. Single-quoted dotenv values are kept literally.
+func (c *ConfigLoader) UseInterpolation() *ConfigLoader {
+	c.Interpolation = true
+	return c
+}
+
+// InterpolationError is returned by LoadInto when a variable reference could not be interpolated.
+type InterpolationError struct {
+	Variable string
+	Err      error
+}
+
+func (e *InterpolationError) Error() string {
+	return fmt.Sprintf("interpolation of variable '%s': %s", e.Variable, e.Err)
+}
+
+func (e *InterpolationError) Unwrap() error {
+	return e.Err
+}
+
+// parseInterpolatedConfig works like parseConfig, but interpolates the variable references of the config first.
+// YAML, JSON and TOML configs are decoded before the interpolation, so only string values are interpolated and the
+// interpolated values can't change the structure of the config. The keys of the config are remembered for the
+// interpolation of the following load items.
+func (c *ConfigLoader) parseInterpolatedConfig(r io.Reader, fileExtension ConfigFileExtension, cfg interface{}) (map[string]string, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if fileExtension == DOTENV {
+		return c.parseInterpolatedDotenv(data)
+	}
+
+	content, err := decodeConfigContent(data, fileExtension)
+	if err != nil {
+		return nil, fmt.Errorf("config file parsing error: %s", err.Error())
+	}
+
+	interpolator := newInterpolator(c.lookupEnv, c.interpolationVariables, nil)
+	content, err = interpolator.interpolateContent(content, fileExtension)
+	if err != nil {
+		return nil, err
+	}
+
+	interpolatedData, err := encodeConfigContent(content, fileExtension)
+	if err != nil {
+		return nil, fmt.Errorf("config file parsing error: %s", err.Error())
+	}
+
+	if _, err := parseConfig(bytes.NewReader(interpolatedData), fileExtension, cfg); err != nil {
+		return nil, err
+	}
+
+	flattenConfigKeys("", content, c.interpolationVariables)
+	return nil, nil
+}
+
+func (c *ConfigLoader) parseInterpolatedDotenv(data []byte) (map[string]string, error) {
+	escapedData := strings.ReplaceAll(string(data), "$", dotenvDollarPlaceholder)
+	escapedDefinitions, err := godotenv.Parse(strings.NewReader(escapedData))
+	if err != nil {
+		return nil, fmt.Errorf("config file parsing error: %s", err.Error())
+	}
+
+	definitions := map[string]string{}
+	for name, value := range escapedDefinitions {
+		definitions[name] = strings.ReplaceAll(value, dotenvDollarPlaceholder, "$")
+	}
+
+	interpolator := newInterpolator(c.lookupEnv, c.interpolationVariables, definitions)
+	interpolator.bareReferences = true
+	for name, singleQuoted := range singleQuotedDotenvNames(string(data)) {
+		// Single-quoted values are literal, like in a shell.
+		if value, ok := definitions[name]; ok && singleQuoted && !strings.HasPrefix(value, "'") {
+			interpolator.resolved[name] = value
+		}
+	}
+
+	variables := map[string]string{}
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, _, err := interpolator.lookup(name)
+		if err != nil {
+			return nil, err
+		}
+
+		variables[name] = value
+	}
+
+	for name, value := range variables {
+		c.interpolationVariables[name] = value
+	}
+
+	return variables, nil
+}
+
+// singleQuotedDotenvNames returns whether the (last) value of each name of a dotenv file is single-quoted. Names and
+// values are separated like godotenv does it.
+func singleQuotedDotenvNames(data string) map[string]bool {
+	names := map[string]bool{}
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		trimmedLine := strings.Trim(line, " \t")
+		if trimmedLine == "" || strings.HasPrefix(trimmedLine, "#") {
+			continue
+		}
+
+		separator := strings.IndexAny(trimmedLine, "=:")
+		if separator < 0 {
+			continue
+		}
+
+		name := strings.Trim(strings.TrimPrefix(trimmedLine[:separator], "export"), " ")
+		names[name] = strings.HasPrefix(strings.Trim(trimmedLine[separator+1:], " "), "'")
+	}
+
+	return names
+}
+
+type interpolator struct {
+	lookupEnv   func(name string) (string, bool)
+	variables   map[string]string
+	definitions map[string]string
+	resolved    map[string]string
+	resolving   []string
+	// bareReferences enables references like '$NAME' without braces, which godotenv expands in dotenv files as well.
+	bareReferences bool
+}
+
+func newInterpolator(lookupEnv func(name string) (string, bool), variables map[string]string, definitions map[string]string) *interpolator {
+	return &interpolator{
+		lookupEnv:   lookupEnv,
+		variables:   variables,
+		definitions: definitions,
+		resolved:    map[string]string{},
+	}
+}
+
+// interpolateContent interpolates all string values of a decoded config. Interpolated YAML values which are plain
+// numbers or booleans keep their type, so they can still be loaded into number and bool fields.
+func (i *interpolator) interpolateContent(content interface{}, fileExtension ConfigFileExtension) (interface{}, error) {
+	var err error
+	switch typedContent := content.(type) {
+	case string:
+		interpolated, err := i.interpolate(typedContent)
+		if err != nil {
+			return nil, err
+		}
+
+		if interpolated != typedContent && (fileExtension == YAML || fileExtension == ".yml") {
+			return yamlScalar(interpolated), nil
+		}
+
+		return interpolated, nil
+	case map[interface{}]interface{}:
+		for key, value := range typedContent {
+			if typedContent[key], err = i.interpolateContent(value, fileExtension); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]interface{}:
+		for key, value := range typedContent {
+			if typedContent[key], err = i.interpolateContent(value, fileExtension); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for index, value := range typedContent {
+			if typedContent[index], err = i.interpolateContent(value, fileExtension); err != nil {
+				return nil, err
+			}
+		}
+	case []map[string]interface{}:
+		for _, value := range typedContent {
+			if _, err = i.interpolateContent(value, fileExtension); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return content, nil
+}
+
+func (i *interpolator) interpolate(value string) (string, error) {
+	var builder strings.Builder
+	for pos := 0; pos < len(value); pos++ {
+		if value[pos] != '$' || pos+1 == len(value) {
+			builder.WriteByte(value[pos])
+			continue
+		}
+
+		switch value[pos+1] {
+		case '$':
+			builder.WriteByte('$')
+			pos++
+		case '{':
+			end := closingBrace(value, pos+1)
+			if end < 0 {
+				return "", &InterpolationError{Variable: value[pos:], Err: ErrInvalidVariableSyntax}
+			}
+
+			expanded, err := i.expand(value[pos+2 : end])
+			if err != nil {
+				return "", err
+			}
+
+			builder.WriteString(expanded)
+			pos = end
+		default:
+			end := pos + 1
+			for i.bareReferences && end < len(value) && isBareVariableNameChar(value[end], end == pos+1) {
+				end++
+			}
+
+			if end == pos+1 {
+				builder.WriteByte('$')
+				continue
+			}
+
+			expanded, err := i.expand(value[pos+1 : end])
+			if err != nil {
+				return "", err
+			}
+
+			builder.WriteString(expanded)
+			pos = end - 1
+		}
+	}
+
+	return builder.String(), nil
+}
+
+func (i *interpolator) expand(expression string) (string, error) {
+	nameLength := 0
+	for nameLength < len(expression) && isVariableNameChar(expression[nameLength]) {
+		nameLength++
+	}
+
+	name := expression[:nameLength]
+	if name == "" {
+		return "", &InterpolationError{Variable: expression, Err: ErrInvalidVariableSyntax}
+	}
+
+	value, ok, err := i.lookup(name)
+	if err != nil {
+		return "", err
+	}
+
+	operator, argument := splitVariableOperator(expression[nameLength:])
+	switch operator {
+	case "":
+		return value, nil
+	case ":-":
+		if !ok || value == "" {
+			return i.interpolate(argument)
+		}
+	case "-":
+		if !ok {
+			return i.interpolate(argument)
+		}
+	case ":?", "?":
+		if !ok || (operator == ":?" && value == "") {
+			return "", &InterpolationError{Variable: name, Err: requiredVariableError(argument)}
+		}
+	default:
+		return "", &InterpolationError{Variable: name, Err: ErrInvalidVariableSyntax}
+	}
+
+	return value, nil
+}
+
+func (i *interpolator) lookup(name string) (string, bool, error) {
+	if definition, ok := i.definitions[name]; ok {
+		return i.resolveDefinition(name, definition)
+	}
+
+	if value, ok := i.lookupEnv(name); ok {
+		return value, true, nil
+	}
+
+	value, ok := i.variables[name]
+	return value, ok, nil
+}
+
+func (i *interpolator) resolveDefinition(name string, definition string) (string, bool, error) {
+	if value, ok := i.resolved[name]; ok {
+		return value, true, nil
+	}
+
+	for pos, resolvingName := range i.resolving {
+		if resolvingName == name {
+			cycle := append(append([]string{}, i.resolving[pos:]...), name)
+			return "", false, &InterpolationError{
+				Variable: name,
+				Err:      fmt.Errorf("%w: %s", ErrInterpolationCycle, strings.Join(cycle, " -> ")),
+			}
+		}
+	}
+
+	i.resolving = append(i.resolving, name)
+	value, err := i.interpolate(definition)
+	i.resolving = i.resolving[:len(i.resolving)-1]
+	if err != nil {
+		return "", false, err
+	}
+
+	i.resolved[name] = value
+	return value, true, nil
+}
+
+func closingBrace(value string, openingBrace int) int {
+	depth := 0
+	for pos := openingBrace; pos < len(value); pos++ {
+		switch value[pos] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return pos
+			}
+		}
+	}
+
+	return -1
+}
+
+func isVariableNameChar(char byte) bool {
+	return char == '_' || char == '.' ||
+		(char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9')
+}
+
+// isBareVariableNameChar reports whether the character belongs to the name of a '$NAME' reference, which has to start
+// with a letter or '_' (so '$5' stays literal) and ends at the first character which isn't a letter, digit or '_'.
+func isBareVariableNameChar(char byte, first bool) bool {
+	return char == '_' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (!first && char >= '0' && char <= '9')
+}
+
+func splitVariableOperator(rest string) (string, string) {
+	for _, operator := range []string{":-", ":?", "-", "?"} {
+		if strings.HasPrefix(rest, operator) {
+			return operator, rest[len(operator):]
+		}
+	}
+
+	return rest, ""
+}
+
+func requiredVariableError(message string) error {
+	if message == "" {
+		return ErrRequiredVariableNotSet
+	}
+
+	return fmt.Errorf("%w: %s", ErrRequiredVariableNotSet, message)
+}
+
+func decodeConfigContent(data []byte, fileExtension ConfigFileExtension) (interface{}, error) {
+	var content interface{}
+	var err error
+	switch fileExtension {
+	case YAML, ".yml":
+		err = yaml.Unmarshal(data, &content)
+	case JSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&content)
+	case TOML:
+		tomlContent := map[string]interface{}{}
+		_, err = toml.Decode(string(data), &tomlContent)
+		content = tomlContent
+	default:
+		return nil, fmt.Errorf("file format '%s' doesn't supported by the parser", fileExtension)
+	}
+
+	return content, err
+}
+
+func encodeConfigContent(content interface{}, fileExtension ConfigFileExtension) ([]byte, error) {
+	switch fileExtension {
+	case YAML, ".yml":
+		return yaml.Marshal(content)
+	case TOML:
+		var buffer bytes.Buffer
+		err := toml.NewEncoder(&buffer).Encode(content)
+		return buffer.Bytes(), err
+	}
+
+	return json.Marshal(content)
+}
+
+// yamlScalar returns the interpolated value as number or boolean, if YAML would decode it into one without changing
+// its text (e.g. '5432' or 'true', but not '0123' or 'yes').
+func yamlScalar(value string) interface{} {
+	var scalar interface{}
+	if err := yaml.Unmarshal([]byte(value), &scalar); err != nil {
+		return value
+	}
+
+	switch scalar.(type) {
+	case int, int64, uint64, float64, bool:
+		if fmt.Sprint(scalar) == value {
+			return scalar
+		}
+	}
+
+	return value
+}
+
+func flattenConfigKeys(prefix string, content interface{}, keys map[string]string) {
+	joinKey := func(key interface{}) string {
+		if prefix == "" {
+			return fmt.Sprint(key)
+		}
+
+		return prefix + "." + fmt.Sprint(key)
+	}
+
+	switch typedContent := content.(type) {
+	case map[interface{}]interface{}:
+		for key, value := range typedContent {
+			flattenConfigKeys(joinKey(key), value, keys)
+		}
+	case map[string]interface{}:
+		for key, value := range typedContent {
+			flattenConfigKeys(joinKey(key), value, keys)
+		}
+	case []interface{}, nil:
+		return
+	default:
+		if prefix != "" {
+			keys[prefix] = fmt.Sprint(typedContent)
+		}
+	}
+}
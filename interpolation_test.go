@@ -0,0 +1,201 @@
+package yetenv
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type interpolationTestConfig struct {
+	Database struct {
+		Host string `yaml:"host"`
+		URL  string `yaml:"url" env:"INTERPOLATION_DATABASE_URL"`
+	} `yaml:"database"`
+	Name    string `yaml:"name"`
+	Price   string `yaml:"price"`
+	Literal string `yaml:"literal"`
+	Port    int    `yaml:"port" json:"port"`
+	Admin   bool   `yaml:"admin" json:"admin"`
+}
+
+func TestConfigLoader_UseInterpolation(t *testing.T) {
+	resetInterpolationEnv := func() {
+		for _, name := range []string{"INTERPOLATION_DB_HOST", "INTERPOLATION_DB_PORT", "INTERPOLATION_DATABASE_URL", "INTERPOLATION_A", "INTERPOLATION_B"} {
+			_ = os.Unsetenv(name)
+		}
+	}
+
+	resetInterpolationEnv()
+	t.Cleanup(resetInterpolationEnv)
+
+	t.Run("should interpolate keys of the same dotenv file and the OS environment", func(t *testing.T) {
+		resetInterpolationEnv()
+		require.NoError(t, os.Setenv("INTERPOLATION_DB_PORT", "5432"))
+
+		c := interpolationTestConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			UseInterpolation().
+			LoadFromBytes([]byte("INTERPOLATION_DATABASE_URL=postgres://${INTERPOLATION_DB_HOST}:${INTERPOLATION_DB_PORT}/app\nINTERPOLATION_DB_HOST=db.internal"), DOTENV).
+			LoadInto(&c)
+		require.NoError(t, err)
+
+		assert.Equal(t, "postgres://db.internal:5432/app", c.Database.URL)
+	})
+
+	t.Run("should interpolate references without braces in dotenv files", func(t *testing.T) {
+		resetInterpolationEnv()
+
+		c := interpolationTestConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			UseInterpolation().
+			LoadFromBytes([]byte("INTERPOLATION_DB_HOST=db\nINTERPOLATION_DATABASE_URL=postgres://$INTERPOLATION_DB_HOST/app?cost=$5"), DOTENV).
+			LoadFromBytes([]byte("name: $INTERPOLATION_DB_HOST"), YAML).
+			LoadInto(&c)
+		require.NoError(t, err)
+
+		assert.Equal(t, "postgres://db/app?cost=$5", c.Database.URL)
+		assert.Equal(t, "$INTERPOLATION_DB_HOST", c.Name)
+	})
+
+	t.Run("should not interpolate single-quoted dotenv values", func(t *testing.T) {
+		resetInterpolationEnv()
+
+		c := interpolationTestConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			UseInterpolation().
+			LoadFromBytes([]byte("INTERPOLATION_DB_HOST='lit$$eral'\nexport INTERPOLATION_DATABASE_URL='${INTERPOLATION_DB_HOST}'\nINTERPOLATION_A=${INTERPOLATION_DB_HOST}"), DOTENV).
+			LoadInto(&c)
+		require.NoError(t, err)
+
+		assert.Equal(t, "${INTERPOLATION_DB_HOST}", c.Database.URL)
+		assert.Equal(t, "lit$$eral", os.Getenv("INTERPOLATION_DB_HOST"))
+		assert.Equal(t, "lit$$eral", os.Getenv("INTERPOLATION_A"))
+	})
+
+	t.Run("should interpolate keys of earlier load items", func(t *testing.T) {
+		resetInterpolationEnv()
+
+		c := interpolationTestConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			UseInterpolation().
+			LoadFromBytes([]byte("INTERPOLATION_DB_HOST=db.internal"), DOTENV).
+			LoadFromBytes([]byte(`{"database": {"host": "${INTERPOLATION_DB_HOST}"}, "name": "app"}`), JSON).
+			LoadFromBytes([]byte("name: ${name}-${database.host}\nprice: $$5\nliteral: ${INTERPOLATION_UNSET:-${name}}"), YAML).
+			LoadInto(&c)
+		require.NoError(t, err)
+
+		assert.Equal(t, "db.internal", c.Database.Host)
+		assert.Equal(t, "app-db.internal", c.Name)
+		assert.Equal(t, "$5", c.Price)
+		assert.Equal(t, "app", c.Literal)
+	})
+
+	t.Run("should interpolate values of numbers in yaml", func(t *testing.T) {
+		resetInterpolationEnv()
+		require.NoError(t, os.Setenv("INTERPOLATION_DB_PORT", "5432"))
+
+		c := interpolationTestConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			UseInterpolation().
+			LoadFromBytes([]byte("port: ${INTERPOLATION_DB_PORT}\nname: ${INTERPOLATION_UNSET:-0123}"), YAML).
+			LoadInto(&c)
+		require.NoError(t, err)
+
+		assert.Equal(t, 5432, c.Port)
+		assert.Equal(t, "0123", c.Name)
+	})
+
+	t.Run("should not change the structure of the config by interpolated values", func(t *testing.T) {
+		resetInterpolationEnv()
+		require.NoError(t, os.Setenv("INTERPOLATION_A", `x", "admin": true, "name": "`))
+
+		c := interpolationTestConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			UseInterpolation().
+			LoadFromBytes([]byte(`{"name": "${INTERPOLATION_A}"}`), JSON).
+			LoadFromBytes([]byte("price: ${INTERPOLATION_A}\nliteral: \"${INTERPOLATION_A}\""), YAML).
+			LoadInto(&c)
+		require.NoError(t, err)
+
+		assert.Equal(t, `x", "admin": true, "name": "`, c.Name)
+		assert.Equal(t, `x", "admin": true, "name": "`, c.Price)
+		assert.Equal(t, `x", "admin": true, "name": "`, c.Literal)
+		assert.False(t, c.Admin)
+	})
+
+	t.Run("should not interpolate comments", func(t *testing.T) {
+		resetInterpolationEnv()
+
+		c := interpolationTestConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			UseInterpolation().
+			LoadFromBytes([]byte("# name: ${INTERPOLATION_UNSET:?must be set}\nname: app"), YAML).
+			LoadFromBytes([]byte("# price = \"${INTERPOLATION_UNSET:?must be set}\"\nprice = \"${name}\""), TOML).
+			LoadInto(&c)
+		require.NoError(t, err)
+
+		assert.Equal(t, "app", c.Name)
+		assert.Equal(t, "app", c.Price)
+	})
+
+	t.Run("should return error for unset required variables", func(t *testing.T) {
+		resetInterpolationEnv()
+
+		c := interpolationTestConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			UseInterpolation().
+			LoadFromBytes([]byte("name: ${INTERPOLATION_UNSET:?must be set}"), YAML).
+			LoadInto(&c)
+
+		assert.True(t, errors.Is(err, ErrRequiredVariableNotSet))
+		assert.EqualError(t, err, "interpolation of variable 'INTERPOLATION_UNSET': required variable is not set: must be set")
+	})
+
+	t.Run("should return error for cycles", func(t *testing.T) {
+		resetInterpolationEnv()
+
+		c := interpolationTestConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			UseInterpolation().
+			LoadFromBytes([]byte("INTERPOLATION_A=${INTERPOLATION_B}\nINTERPOLATION_B=x${INTERPOLATION_A}"), DOTENV).
+			LoadInto(&c)
+
+		assert.True(t, errors.Is(err, ErrInterpolationCycle))
+		assert.EqualError(t, err, "interpolation of variable 'INTERPOLATION_A': variable reference cycle: "+
+			"INTERPOLATION_A -> INTERPOLATION_B -> INTERPOLATION_A")
+	})
+
+	t.Run("should return error for invalid references", func(t *testing.T) {
+		c := interpolationTestConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			UseInterpolation().
+			LoadFromBytes([]byte("name: ${name"), YAML).
+			LoadInto(&c)
+
+		assert.True(t, errors.Is(err, ErrInvalidVariableSyntax))
+	})
+
+	t.Run("should not interpolate without UseInterpolation", func(t *testing.T) {
+		c := interpolationTestConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			LoadFromBytes([]byte("name: ${name}"), YAML).
+			LoadInto(&c)
+		require.NoError(t, err)
+
+		assert.Equal(t, "${name}", c.Name)
+	})
+}
@@ -0,0 +1,41 @@
+package yetenv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Load will finish the ConfigLoader like LoadInto and returns the loaded config. T must be a struct or a pointer to
+// a struct, which is checked before anything is loaded.
+func Load[T any](configLoader *ConfigLoader) (T, error) {
+	var cfg T
+
+	cfgType := reflect.TypeOf((*T)(nil)).Elem()
+	switch {
+	case cfgType.Kind() == reflect.Struct:
+		if err := configLoader.LoadInto(&cfg); err != nil {
+			return cfg, err
+		}
+	case cfgType.Kind() == reflect.Ptr && cfgType.Elem().Kind() == reflect.Struct:
+		cfgValue := reflect.New(cfgType.Elem())
+		if err := configLoader.LoadInto(cfgValue.Interface()); err != nil {
+			return cfg, err
+		}
+
+		cfg = cfgValue.Interface().(T)
+	default:
+		return cfg, fmt.Errorf("%w: got %s", ErrNoConfigStruct, cfgType)
+	}
+
+	return cfg, nil
+}
+
+// MustLoad works like Load but panics when the config could not be loaded.
+func MustLoad[T any](configLoader *ConfigLoader) T {
+	cfg, err := Load[T](configLoader)
+	if err != nil {
+		panic(err)
+	}
+
+	return cfg
+}
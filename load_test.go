@@ -0,0 +1,68 @@
+package yetenv
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type loadTestConfig struct {
+	Name string `yaml:"name"`
+}
+
+func TestLoad(t *testing.T) {
+	newConfigLoader := func() *ConfigLoader {
+		return NewConfigLoader().
+			UseCustomLoadBehavior().
+			LoadFromBytes([]byte("name: loaded"), YAML)
+	}
+
+	t.Run("should return loaded struct", func(t *testing.T) {
+		cfg, err := Load[loadTestConfig](newConfigLoader())
+		require.NoError(t, err)
+
+		assert.Equal(t, loadTestConfig{Name: "loaded"}, cfg)
+	})
+
+	t.Run("should return loaded struct pointer", func(t *testing.T) {
+		cfg, err := Load[*loadTestConfig](newConfigLoader())
+		require.NoError(t, err)
+
+		assert.Equal(t, &loadTestConfig{Name: "loaded"}, cfg)
+	})
+
+	t.Run("should return error for non struct types", func(t *testing.T) {
+		_, err := Load[string](newConfigLoader())
+		assert.True(t, errors.Is(err, ErrNoConfigStruct))
+		assert.EqualError(t, err, "config must be a struct or a pointer to a struct: got string")
+
+		_, err = Load[**loadTestConfig](newConfigLoader())
+		assert.EqualError(t, err, "config must be a struct or a pointer to a struct: got **yetenv.loadTestConfig")
+
+		_, err = Load[map[string]string](newConfigLoader())
+		assert.True(t, errors.Is(err, ErrNoConfigStruct))
+	})
+
+	t.Run("should return error of the config loader", func(t *testing.T) {
+		_, err := Load[*loadTestConfig](NewConfigLoader())
+		assert.Equal(t, ErrUnknownLoadBehavior, err)
+	})
+}
+
+func TestMustLoad(t *testing.T) {
+	t.Run("should return loaded struct", func(t *testing.T) {
+		cfg := MustLoad[loadTestConfig](NewConfigLoader().
+			UseCustomLoadBehavior().
+			LoadFromBytes([]byte("name: loaded"), YAML))
+
+		assert.Equal(t, "loaded", cfg.Name)
+	})
+
+	t.Run("should panic when config could not be loaded", func(t *testing.T) {
+		assert.PanicsWithValue(t, ErrUnknownLoadBehavior, func() {
+			MustLoad[loadTestConfig](NewConfigLoader())
+		})
+	})
+}
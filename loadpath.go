@@ -0,0 +1,121 @@
+package yetenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// LoadPathResolution defines how relative load paths and search paths are resolved.
+type LoadPathResolution int
+
+const (
+	// LoadPathRelativeToWorkingDirectory resolves relative paths against the current working directory.
+	LoadPathRelativeToWorkingDirectory LoadPathResolution = iota
+	// LoadPathRelativeToExecutable resolves relative paths against the directory of the running executable.
+	LoadPathRelativeToExecutable
+	// LoadPathRelativeToProjectRoot resolves relative paths against the nearest ancestor of the working directory
+	// which contains one of the ProjectRootMarkers.
+	LoadPathRelativeToProjectRoot
+)
+
+// DefaultProjectRootMarkers defines the default files or directories which mark the project root.
+var DefaultProjectRootMarkers = []string{"go.mod", ".git"}
+
+var (
+	ErrProjectRootNotFound = errors.New("project root could not be found - no ancestor directory contains a project root marker")
+)
+
+// UseLoadPathRelativeToExecutable resolves relative load paths and search paths against the directory of the running
+// executable (os.Executable) instead of the working directory.
+func (c *ConfigLoader) UseLoadPathRelativeToExecutable() *ConfigLoader {
+	c.LoadPathResolution = LoadPathRelativeToExecutable
+	return c
+}
+
+// UseLoadPathRelativeToProjectRoot resolves relative load paths and search paths against the nearest ancestor of the
+// working directory which contains one of the provided markers. The markers default to DefaultProjectRootMarkers.
+func (c *ConfigLoader) UseLoadPathRelativeToProjectRoot(markers ...string) *ConfigLoader {
+	c.LoadPathResolution = LoadPathRelativeToProjectRoot
+	if len(markers) == 0 {
+		markers = DefaultProjectRootMarkers
+	}
+
+	c.ProjectRootMarkers = markers
+	return c
+}
+
+func (c *ConfigLoader) resolveSearchPaths() ([]string, error) {
+	searchPaths := c.SearchPaths
+	if len(searchPaths) == 0 {
+		searchPaths = []string{c.LoadPath}
+	}
+
+	if c.LoadPathResolution == LoadPathRelativeToWorkingDirectory {
+		return searchPaths, nil
+	}
+
+	baseDir, err := c.loadPathBaseDir()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvedPaths := make([]string, 0, len(searchPaths))
+	for _, searchPath := range searchPaths {
+		if !filepath.IsAbs(searchPath) {
+			searchPath = filepath.Join(baseDir, searchPath)
+		}
+
+		resolvedPaths = append(resolvedPaths, searchPath)
+	}
+
+	return resolvedPaths, nil
+}
+
+func (c *ConfigLoader) loadPathBaseDir() (string, error) {
+	switch c.LoadPathResolution {
+	case LoadPathRelativeToExecutable:
+		return executableDir()
+	case LoadPathRelativeToProjectRoot:
+		workingDir, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+
+		return findProjectRoot(workingDir, c.ProjectRootMarkers)
+	}
+
+	return os.Getwd()
+}
+
+func executableDir() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+
+	executable, err = filepath.EvalSymlinks(executable)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Dir(executable), nil
+}
+
+func findProjectRoot(startDir string, markers []string) (string, error) {
+	dir := startDir
+	for {
+		for _, marker := range markers {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				return dir, nil
+			}
+		}
+
+		parentDir := filepath.Dir(dir)
+		if parentDir == dir {
+			return "", ErrProjectRootNotFound
+		}
+
+		dir = parentDir
+	}
+}
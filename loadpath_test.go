@@ -0,0 +1,102 @@
+package yetenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigLoader_UseLoadPathRelativeToExecutable(t *testing.T) {
+	configLoader := NewConfigLoader()
+	require.Equal(t, LoadPathRelativeToWorkingDirectory, configLoader.LoadPathResolution)
+
+	configLoader.
+		UseLoadPath("config").
+		UseLoadPathRelativeToExecutable().
+		LoadFromFileForEnvironment(Production)
+
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	executable, err = filepath.EvalSymlinks(executable)
+	require.NoError(t, err)
+
+	assert.Equal(t, LoadPathRelativeToExecutable, configLoader.LoadPathResolution)
+	assert.Equal(t, filepath.Join(filepath.Dir(executable), "config", "cfg.prod.env"), configLoader.loadOrder[0].file)
+}
+
+func TestConfigLoader_UseLoadPathRelativeToProjectRoot(t *testing.T) {
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	t.Run("should set default markers", func(t *testing.T) {
+		configLoader := NewConfigLoader().UseLoadPathRelativeToProjectRoot()
+
+		assert.Equal(t, LoadPathRelativeToProjectRoot, configLoader.LoadPathResolution)
+		assert.Equal(t, DefaultProjectRootMarkers, configLoader.ProjectRootMarkers)
+	})
+
+	t.Run("should load files relative to project root from nested directory", func(t *testing.T) {
+		resetEnv()
+
+		require.NoError(t, os.Chdir(filepath.Join("testdata", "search", "first")))
+		defer func() { _ = os.Chdir(workingDir) }()
+
+		c := testConfig{}
+		err := NewConfigLoader().
+			UseLoadPath("testdata").
+			UseLoadPathRelativeToProjectRoot("go.mod").
+			UseEnvironment(Production).
+			UseDefaultLoadBehavior().
+			LoadInto(&c)
+
+		assert.NoError(t, err)
+		assert.True(t, c.Production)
+		assert.True(t, c.Custom)
+	})
+
+	t.Run("should keep absolute paths", func(t *testing.T) {
+		absolutePath := filepath.Join(workingDir, "testdata")
+
+		configLoader := NewConfigLoader().
+			UseSearchPaths(absolutePath).
+			UseLoadPathRelativeToProjectRoot("marker-which-does-not-exist", "go.mod").
+			LoadFromFileForEnvironment(Production)
+
+		assert.Equal(t, filepath.Join(absolutePath, "cfg.prod.env"), configLoader.loadOrder[0].file)
+	})
+
+	t.Run("should return error when project root can not be found", func(t *testing.T) {
+		c := testConfig{}
+		err := NewConfigLoader().
+			UseLoadPathRelativeToProjectRoot("marker-which-does-not-exist").
+			UseEnvironment(Production).
+			UseDefaultLoadBehavior().
+			LoadInto(&c)
+
+		assert.Equal(t, ErrProjectRootNotFound, err)
+	})
+
+	t.Run("should not keep resolution error for later loads", func(t *testing.T) {
+		resetEnv()
+
+		configLoader := NewConfigLoader().
+			UseLoadPath("testdata").
+			UseLoadPathRelativeToProjectRoot("go.mod").
+			UseEnvironment(Production).
+			UseDefaultLoadBehavior()
+
+		require.NoError(t, os.Chdir(t.TempDir()))
+		c := testConfig{}
+		err := configLoader.LoadInto(&c)
+		require.NoError(t, os.Chdir(workingDir))
+		assert.Equal(t, ErrProjectRootNotFound, err)
+
+		c = testConfig{}
+		err = configLoader.LoadInto(&c)
+		assert.NoError(t, err)
+		assert.True(t, c.Production)
+	})
+}
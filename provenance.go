@@ -0,0 +1,193 @@
+package yetenv
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SourceType defines the type of source which set a config field.
+type SourceType int
+
+const (
+	SourceUnknown SourceType = iota
+	SourceFile
+	SourceReader
+	SourceBytes
+	SourceEnvVariable
+	SourceDefault
+)
+
+// FieldSource describes the source which set a config field during LoadInto.
+type FieldSource struct {
+	Type SourceType
+	// Name is the path of the config file or the name of the environment variable.
+	Name string
+	// Step is the number of the load step (starting with 1) in which the field was set.
+	Step int
+}
+
+func (s FieldSource) String() string {
+	switch s.Type {
+	case SourceFile:
+		return fmt.Sprintf("file '%s' (step %d)", s.Name, s.Step)
+	case SourceReader:
+		return fmt.Sprintf("reader (step %d)", s.Step)
+	case SourceBytes:
+		return fmt.Sprintf("bytes (step %d)", s.Step)
+	case SourceEnvVariable:
+		return fmt.Sprintf("environment variable '%s' (step %d)", s.Name, s.Step)
+	case SourceDefault:
+		return fmt.Sprintf("default value (step %d)", s.Step)
+	}
+
+	return "unknown"
+}
+
+// Provenance maps struct field paths (e.g. 'Database.Host') to the source which set the field last.
+// Fields which were not changed by any load step are not part of the Provenance.
+type Provenance map[string]FieldSource
+
+// Fields returns the field paths of the Provenance in sorted order.
+func (p Provenance) Fields() []string {
+	fields := make([]string, 0, len(p))
+	for field := range p {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+	return fields
+}
+
+// String returns one line per field in sorted order, so it can be logged at startup.
+func (p Provenance) String() string {
+	var builder strings.Builder
+	for _, field := range p.Fields() {
+		builder.WriteString(fmt.Sprintf("%s: %s\n", field, p[field]))
+	}
+
+	return builder.String()
+}
+
+// Provenance returns the source of every config field which was set by the last LoadInto call.
+// A field is attributed to a source when its value changed while loading the source.
+func (c *ConfigLoader) Provenance() Provenance {
+	if c.provenance == nil {
+		return Provenance{}
+	}
+
+	return c.provenance.provenance
+}
+
+// provenanceTracker detects changed config fields by comparing snapshots before and after each load step.
+type provenanceTracker struct {
+	provenance    Provenance
+	step          int
+	snapshot      map[string]interface{}
+	dotenvSources map[string]dotenvSource
+}
+
+type dotenvSource struct {
+	value  string
+	source FieldSource
+}
+
+func newProvenanceTracker() *provenanceTracker {
+	return &provenanceTracker{
+		provenance:    Provenance{},
+		dotenvSources: map[string]dotenvSource{},
+	}
+}
+
+func (t *provenanceTracker) beginStep(cfg interface{}) {
+	t.step++
+	t.snapshot = takeSnapshot(cfg)
+}
+
+func (t *provenanceTracker) recordParsedSource(cfg interface{}, source FieldSource, dotenvVariables map[string]string) {
+	source.Step = t.step
+
+	for name, value := range dotenvVariables {
+		t.dotenvSources[name] = dotenvSource{value: value, source: source}
+	}
+
+	for _, field := range t.changedFields(cfg) {
+		t.provenance[field.path] = source
+	}
+}
+
+func (t *provenanceTracker) recordEnvVariables(cfg interface{}) {
+	for _, field := range t.changedFields(cfg) {
+		t.provenance[field.path] = t.envSourceForField(field)
+	}
+}
+
+func (t *provenanceTracker) envSourceForField(field structField) FieldSource {
+	for _, envName := range field.envNames {
+		value, ok := os.LookupEnv(envName)
+		if !ok {
+			continue
+		}
+
+		if dotenv, ok := t.dotenvSources[envName]; ok && dotenv.value == value {
+			return dotenv.source
+		}
+
+		return FieldSource{Type: SourceEnvVariable, Name: envName, Step: t.step}
+	}
+
+	return FieldSource{Type: SourceDefault, Step: t.step}
+}
+
+func (t *provenanceTracker) changedFields(cfg interface{}) []structField {
+	var changedFields []structField
+	currentSnapshot := map[string]interface{}{}
+
+	for _, field := range collectStructFields(cfg) {
+		currentSnapshot[field.path] = copyValue(field.value)
+
+		if !reflect.DeepEqual(t.snapshot[field.path], field.value.Interface()) {
+			changedFields = append(changedFields, field)
+		}
+	}
+
+	t.snapshot = currentSnapshot
+	return changedFields
+}
+
+func takeSnapshot(cfg interface{}) map[string]interface{} {
+	snapshot := map[string]interface{}{}
+	for _, field := range collectStructFields(cfg) {
+		snapshot[field.path] = copyValue(field.value)
+	}
+
+	return snapshot
+}
+
+// copyValue copies slices and maps, so in-place changes by the parsers are detected.
+func copyValue(value reflect.Value) interface{} {
+	switch value.Kind() {
+	case reflect.Slice:
+		if value.IsNil() {
+			return value.Interface()
+		}
+
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		reflect.Copy(copied, value)
+		return copied.Interface()
+	case reflect.Map:
+		if value.IsNil() {
+			return value.Interface()
+		}
+
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		for _, key := range value.MapKeys() {
+			copied.SetMapIndex(key, value.MapIndex(key))
+		}
+		return copied.Interface()
+	}
+
+	return value.Interface()
+}
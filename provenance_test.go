@@ -0,0 +1,72 @@
+package yetenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type provenanceTestConfig struct {
+	Database struct {
+		Host string `yaml:"host" env:"PROVENANCE_DATABASE_HOST"`
+		Port int    `yaml:"port" env:"PROVENANCE_DATABASE_PORT"`
+	} `yaml:"database"`
+	Name     string `yaml:"name" env:"PROVENANCE_NAME"`
+	LogLevel string `yaml:"log_level" env:"PROVENANCE_LOG_LEVEL" env-default:"info"`
+	Unset    string `yaml:"unset"`
+}
+
+func TestConfigLoader_Provenance(t *testing.T) {
+	resetProvenanceEnv := func() {
+		_ = os.Unsetenv("PROVENANCE_DATABASE_HOST")
+		_ = os.Unsetenv("PROVENANCE_DATABASE_PORT")
+		_ = os.Unsetenv("PROVENANCE_NAME")
+		_ = os.Unsetenv("PROVENANCE_LOG_LEVEL")
+	}
+
+	resetProvenanceEnv()
+	defer resetProvenanceEnv()
+
+	require.NoError(t, os.Setenv("PROVENANCE_NAME", "env-name"))
+
+	yamlFile := filepath.Join("testdata", "provenance.yaml")
+	dotenvFile := filepath.Join("testdata", "provenance.env")
+
+	c := provenanceTestConfig{}
+	configLoader := NewConfigLoader().
+		UseCustomLoadBehavior().
+		LoadFromFile(yamlFile).
+		LoadFromFile(dotenvFile).
+		LoadFromBytes([]byte(`{"database": {"port": 6543}}`), JSON)
+
+	err := configLoader.LoadInto(&c)
+	require.NoError(t, err)
+
+	expectedProvenance := Provenance{
+		"Database.Host": {Type: SourceFile, Name: dotenvFile, Step: 2},
+		"Database.Port": {Type: SourceBytes, Step: 3},
+		"Name":          {Type: SourceEnvVariable, Name: "PROVENANCE_NAME", Step: 1},
+		"LogLevel":      {Type: SourceDefault, Step: 1},
+	}
+
+	assert.Equal(t, "dotenv-host", c.Database.Host)
+	assert.Equal(t, 6543, c.Database.Port)
+	assert.Equal(t, "env-name", c.Name)
+	assert.Equal(t, expectedProvenance, configLoader.Provenance())
+	assert.Equal(t, []string{"Database.Host", "Database.Port", "LogLevel", "Name"}, configLoader.Provenance().Fields())
+}
+
+func TestProvenance_String(t *testing.T) {
+	provenance := Provenance{
+		"Name":          {Type: SourceEnvVariable, Name: "NAME", Step: 1},
+		"Database.Host": {Type: SourceFile, Name: "cfg.prod.env", Step: 2},
+	}
+
+	expectedString := "Database.Host: file 'cfg.prod.env' (step 2)\n" +
+		"Name: environment variable 'NAME' (step 1)\n"
+
+	assert.Equal(t, expectedString, provenance.String())
+}
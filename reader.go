@@ -0,0 +1,79 @@
+package yetenv
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// LoadFromReader can be used to load a config from a reader (e.g. os.Stdin) in the provided format.
+// The reader is read once when the load item is loaded for the first time.
+func (c *ConfigLoader) LoadFromReader(r io.Reader, format ConfigFileExtension) *ConfigLoader {
+	return c.LoadFromConditionalReader(r, format, nil)
+}
+
+// LoadFromConditionalReader works like LoadFromReader, but the config is only loaded when the condition of the
+// conditionFunc is met.
+func (c *ConfigLoader) LoadFromConditionalReader(r io.Reader, format ConfigFileExtension, conditionFunc ConditionalLoadFunc) *ConfigLoader {
+	c.loadOrder = append(c.loadOrder, loadOrderItem{
+		source:        &readerSource{reader: r, format: format, sourceType: SourceReader},
+		conditionFunc: conditionFunc,
+	})
+
+	return c
+}
+
+// LoadFromBytes can be used to load a config from raw bytes in the provided format.
+func (c *ConfigLoader) LoadFromBytes(data []byte, format ConfigFileExtension) *ConfigLoader {
+	return c.LoadFromConditionalBytes(data, format, nil)
+}
+
+// LoadFromConditionalBytes works like LoadFromBytes, but the config is only loaded when the condition of the
+// conditionFunc is met.
+func (c *ConfigLoader) LoadFromConditionalBytes(data []byte, format ConfigFileExtension, conditionFunc ConditionalLoadFunc) *ConfigLoader {
+	c.loadOrder = append(c.loadOrder, loadOrderItem{
+		source:        &readerSource{data: data, read: true, format: format, sourceType: SourceBytes},
+		conditionFunc: conditionFunc,
+	})
+
+	return c
+}
+
+func (c *ConfigLoader) loadConfigFromSource(source *readerSource, cfg interface{}) error {
+	data, err := source.bytes()
+	if err != nil {
+		return err
+	}
+
+	return c.readConfig(bytes.NewReader(data), source.format, FieldSource{Type: source.sourceType}, cfg)
+}
+
+// readerSource keeps the content of a reader, so it can be loaded more than once.
+type readerSource struct {
+	mutex      sync.Mutex
+	reader     io.Reader
+	data       []byte
+	read       bool
+	format     ConfigFileExtension
+	sourceType SourceType
+}
+
+func (s *readerSource) bytes() ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.read {
+		return s.data, nil
+	}
+
+	data, err := ioutil.ReadAll(s.reader)
+	if err != nil {
+		return nil, err
+	}
+
+	s.data = data
+	s.read = true
+
+	return s.data, nil
+}
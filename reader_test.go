@@ -0,0 +1,102 @@
+package yetenv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigLoader_LoadFromReader(t *testing.T) {
+	t.Run("should load config from reader", func(t *testing.T) {
+		resetEnv()
+
+		c := testConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			LoadFromFile("./testdata/cfg.dev.yaml").
+			LoadFromReader(strings.NewReader("prod: true\nlast_file: \"reader\""), YAML).
+			LoadInto(&c)
+
+		expectedConfig := testConfig{
+			Develop:    true,
+			Production: true,
+			LastFile:   "reader",
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedConfig, c)
+	})
+
+	t.Run("should keep content of reader for subsequent loads", func(t *testing.T) {
+		resetEnv()
+
+		configLoader := NewConfigLoader().
+			UseCustomLoadBehavior().
+			LoadFromReader(strings.NewReader(`{"Custom": true}`), JSON)
+
+		c := testConfig{}
+		require.NoError(t, configLoader.LoadInto(&c))
+		assert.True(t, c.Custom)
+
+		c = testConfig{}
+		require.NoError(t, configLoader.LoadInto(&c))
+		assert.True(t, c.Custom)
+	})
+
+	t.Run("should only load reader when condition is met", func(t *testing.T) {
+		resetEnv()
+
+		c := testConfig{}
+		err := NewConfigLoader().
+			UseEnvironment(Develop).
+			UseCustomLoadBehavior().
+			LoadFromConditionalReader(strings.NewReader(`STAGING="true"`), DOTENV, DefaultConditionForStagingEnvironment).
+			LoadFromConditionalReader(strings.NewReader(`DEVELOP="true"`), DOTENV, DefaultConditionForDevelopEnvironment).
+			LoadInto(&c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, testConfig{Develop: true}, c)
+	})
+}
+
+func TestConfigLoader_LoadFromBytes(t *testing.T) {
+	t.Run("should load config from bytes", func(t *testing.T) {
+		resetEnv()
+
+		c := testConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			LoadFromBytes([]byte("Test = true\nLastFile = \"bytes\""), TOML).
+			LoadInto(&c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, testConfig{Test: true, LastFile: "bytes"}, c)
+	})
+
+	t.Run("should only load bytes when condition is met", func(t *testing.T) {
+		resetEnv()
+
+		c := testConfig{}
+		err := NewConfigLoader().
+			UseEnvironment(Production).
+			UseCustomLoadBehavior().
+			LoadFromConditionalBytes([]byte(`PROD="true"`), DOTENV, DefaultConditionForProductionEnvironment).
+			LoadFromConditionalBytes([]byte(`TEST="true"`), DOTENV, DefaultConditionForTestEnvironment).
+			LoadInto(&c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, testConfig{Production: true}, c)
+	})
+
+	t.Run("should return error for invalid content", func(t *testing.T) {
+		c := testConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			LoadFromBytes([]byte(`{"Custom": `), JSON).
+			LoadInto(&c)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,165 @@
+package yetenv
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrEmptyEnvironment             = errors.New("environment must not be empty")
+	ErrEnvironmentAlreadyRegistered = errors.New("environment is already registered")
+	ErrVariableValueAlreadyUsed     = errors.New("environment variable value is already used")
+)
+
+// EnvironmentRegistration describes a user-defined Environment which can be registered by RegisterEnvironment.
+type EnvironmentRegistration struct {
+	// Environment is the new Environment value (e.g. "qa").
+	Environment Environment
+	// VariableValues are the accepted (case-insensitive) values of the environment variable.
+	// The Environment value itself is always accepted.
+	VariableValues []string
+	// ConfigFile is the default config file name without extension. Defaults to 'cfg.<environment>'.
+	ConfigFile string
+	// RanksLike defines the built-in environment which is used to rank the Environment by IsAtLeast (e.g. Staging).
+	RanksLike Environment
+}
+
+var (
+	registryMutex          sync.RWMutex
+	registeredEnvironments []EnvironmentRegistration
+)
+
+// RegisterEnvironment registers a user-defined Environment. Registered environments are detected by GetEnvironment
+// and GetEnvironmentFromVariable, and are part of the config files and the default load behavior of every ConfigLoader
+// created afterwards.
+// An error wrapping ErrVariableValueAlreadyUsed is returned when the Environment or one of the VariableValues is
+// already an alias (e.g. 'local' or 'prod') or a value of another registered environment, because it could never be
+// detected.
+func RegisterEnvironment(registration EnvironmentRegistration) error {
+	if registration.Environment == "" {
+		return ErrEmptyEnvironment
+	}
+
+	if isBuiltInEnvironment(registration.Environment) {
+		return ErrEnvironmentAlreadyRegistered
+	}
+
+	if registration.ConfigFile == "" {
+		registration.ConfigFile = defaultCustomConfigFile + "." + string(registration.Environment)
+	}
+
+	variableValues := []string{normalizeVariableValue(string(registration.Environment))}
+	for _, value := range registration.VariableValues {
+		variableValues = append(variableValues, normalizeVariableValue(value))
+	}
+	registration.VariableValues = variableValues
+
+	for _, value := range variableValues {
+		if _, ok := environmentFromAlias(value); ok {
+			return fmt.Errorf("%w: '%s' is an alias", ErrVariableValueAlreadyUsed, value)
+		}
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	for _, registered := range registeredEnvironments {
+		if registered.Environment == registration.Environment {
+			return ErrEnvironmentAlreadyRegistered
+		}
+
+		for _, registeredValue := range registered.VariableValues {
+			for _, value := range variableValues {
+				if value == registeredValue {
+					return fmt.Errorf("%w: '%s' belongs to environment '%s'", ErrVariableValueAlreadyUsed, value, registered.Environment)
+				}
+			}
+		}
+	}
+
+	registeredEnvironments = append(registeredEnvironments, registration)
+
+	return nil
+}
+
+// UnregisterEnvironment removes a previously registered Environment. Built-in environments can not be unregistered.
+func UnregisterEnvironment(environment Environment) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	for i, registered := range registeredEnvironments {
+		if registered.Environment == environment {
+			registeredEnvironments = append(registeredEnvironments[:i], registeredEnvironments[i+1:]...)
+			return
+		}
+	}
+}
+
+// RegisteredEnvironments returns all user-defined environments in the order of their registration.
+func RegisteredEnvironments() []Environment {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	environments := make([]Environment, 0, len(registeredEnvironments))
+	for _, registered := range registeredEnvironments {
+		environments = append(environments, registered.Environment)
+	}
+
+	return environments
+}
+
+func registeredEnvironmentFromVariableValue(variableValue string) (Environment, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	for _, registered := range registeredEnvironments {
+		for _, value := range registered.VariableValues {
+			if value == variableValue {
+				return registered.Environment, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func registeredEnvironmentRanksLike(environment Environment) (Environment, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	for _, registered := range registeredEnvironments {
+		if registered.Environment == environment && registered.RanksLike != "" {
+			return registered.RanksLike, true
+		}
+	}
+
+	return "", false
+}
+
+func registeredConfigFiles() map[Environment]string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	configFiles := map[Environment]string{}
+	for _, registered := range registeredEnvironments {
+		configFiles[registered.Environment] = registered.ConfigFile
+	}
+
+	return configFiles
+}
+
+func isBuiltInEnvironment(environment Environment) bool {
+	switch environment {
+	case Production, Staging, Test, Develop, Custom:
+		return true
+	}
+
+	return false
+}
+
+func conditionForEnvironment(environment Environment) ConditionalLoadFunc {
+	return func(configLoader *ConfigLoader, currentEnvironment Environment) bool {
+		return currentEnvironment == environment
+	}
+}
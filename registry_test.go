@@ -0,0 +1,105 @@
+package yetenv
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type registryTestConfig struct {
+	QA       bool   `env:"QA"`
+	Custom   bool   `env:"CUSTOM"`
+	LastFile string `env:"LAST_FILE"`
+}
+
+func TestRegisterEnvironment(t *testing.T) {
+	t.Run("should return error for empty or built-in environments", func(t *testing.T) {
+		assert.Equal(t, ErrEmptyEnvironment, RegisterEnvironment(EnvironmentRegistration{}))
+		assert.Equal(t, ErrEnvironmentAlreadyRegistered, RegisterEnvironment(EnvironmentRegistration{Environment: Production}))
+	})
+
+	t.Run("should return error when registering the same environment twice", func(t *testing.T) {
+		defer UnregisterEnvironment("qa")
+
+		require.NoError(t, RegisterEnvironment(EnvironmentRegistration{Environment: "qa"}))
+		assert.Equal(t, ErrEnvironmentAlreadyRegistered, RegisterEnvironment(EnvironmentRegistration{Environment: "qa"}))
+	})
+
+	t.Run("should return error for variable values which could not be detected", func(t *testing.T) {
+		defer UnregisterEnvironment("qa")
+
+		err := RegisterEnvironment(EnvironmentRegistration{Environment: "local"})
+		assert.True(t, errors.Is(err, ErrVariableValueAlreadyUsed))
+		assert.EqualError(t, err, "environment variable value is already used: 'local' is an alias")
+
+		err = RegisterEnvironment(EnvironmentRegistration{Environment: "live", VariableValues: []string{"PROD"}})
+		assert.True(t, errors.Is(err, ErrVariableValueAlreadyUsed))
+
+		require.NoError(t, RegisterEnvironment(EnvironmentRegistration{Environment: "qa", VariableValues: []string{"quality"}}))
+		err = RegisterEnvironment(EnvironmentRegistration{Environment: "preview", VariableValues: []string{"quality"}})
+		assert.EqualError(t, err, "environment variable value is already used: 'quality' belongs to environment 'qa'")
+		assert.Equal(t, []Environment{"qa"}, RegisteredEnvironments())
+	})
+
+	t.Run("should keep registration order and allow unregistering", func(t *testing.T) {
+		require.NoError(t, RegisterEnvironment(EnvironmentRegistration{Environment: "qa"}))
+		require.NoError(t, RegisterEnvironment(EnvironmentRegistration{Environment: "preview"}))
+		assert.Equal(t, []Environment{"qa", "preview"}, RegisteredEnvironments())
+
+		UnregisterEnvironment("qa")
+		UnregisterEnvironment("preview")
+		assert.Empty(t, RegisteredEnvironments())
+	})
+}
+
+func TestGetEnvironmentFromVariable_RegisteredEnvironment(t *testing.T) {
+	defer UnregisterEnvironment("sandbox")
+
+	require.NoError(t, RegisterEnvironment(EnvironmentRegistration{
+		Environment:    "sandbox",
+		VariableValues: []string{"sbx"},
+	}))
+
+	values := []string{"sandbox", "SANDBOX", "sbx", "SBX"}
+	for _, value := range values {
+		err := os.Setenv("REGISTRY_ENV", value)
+		require.NoError(t, err)
+
+		assert.Equal(t, Environment("sandbox"), GetEnvironmentFromVariable("REGISTRY_ENV"))
+	}
+}
+
+func TestConfigLoader_RegisteredEnvironment(t *testing.T) {
+	defer UnregisterEnvironment("qa")
+
+	require.NoError(t, RegisterEnvironment(EnvironmentRegistration{Environment: "qa"}))
+
+	t.Run("should add config file of registered environment", func(t *testing.T) {
+		configLoader := NewConfigLoader()
+		assert.Equal(t, "cfg.qa", configLoader.ConfigFiles["qa"])
+	})
+
+	t.Run("should load registered environment file in default load behavior", func(t *testing.T) {
+		resetEnv()
+		defer func() { _ = os.Unsetenv("QA") }()
+
+		c := registryTestConfig{}
+		err := NewConfigLoader().
+			UseLoadPath("./testdata").
+			UseEnvironment("qa").
+			UseDefaultLoadBehavior().
+			LoadInto(&c)
+
+		expectedConfig := registryTestConfig{
+			QA:       true,
+			Custom:   true,
+			LastFile: "custom",
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedConfig, c)
+	})
+}
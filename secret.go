@@ -0,0 +1,193 @@
+package yetenv
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const secretSchemeSeparator = "://"
+
+var (
+	ErrSecretNotFound         = errors.New("secret not found")
+	ErrInvalidSecretReference = errors.New("invalid secret reference")
+)
+
+// SecretResolver resolves the reference of a secret (the part after '<scheme>://') into the secret value.
+type SecretResolver func(reference string) (string, error)
+
+// defaultSecretResolvers doesn't contain ExecSecretResolver, because running commands of config values has to be
+// enabled explicitly by RegisterSecretResolver("exec", ExecSecretResolver).
+var defaultSecretResolvers = map[string]SecretResolver{
+	"file": FileSecretResolver,
+	"env":  EnvSecretResolver,
+}
+
+var (
+	secretResolverMutex sync.RWMutex
+	secretResolvers     = copySecretResolvers(defaultSecretResolvers)
+)
+
+// FileSecretResolver resolves 'file:///run/secrets/db_password' into the content of the file (e.g. Docker secrets).
+// A trailing line break is removed.
+func FileSecretResolver(reference string) (string, error) {
+	content, err := ioutil.ReadFile(reference)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// EnvSecretResolver resolves 'env://OTHER_VAR' into the value of the OS environment variable.
+func EnvSecretResolver(reference string) (string, error) {
+	value, ok := os.LookupEnv(reference)
+	if !ok {
+		return "", fmt.Errorf("%w: environment variable '%s' is not set", ErrSecretNotFound, reference)
+	}
+
+	return value, nil
+}
+
+// ExecSecretResolver resolves 'exec://pass show db' into the output of the command. The command is split by
+// whitespace and is not executed by a shell. A trailing line break is removed.
+// It isn't registered by default, as anyone who can change a config value could run commands with it:
+//
+//	yetenv.RegisterSecretResolver("exec", yetenv.ExecSecretResolver)
+func ExecSecretResolver(reference string) (string, error) {
+	command := strings.Fields(reference)
+	if len(command) == 0 {
+		return "", ErrInvalidSecretReference
+	}
+
+	output, err := exec.Command(command[0], command[1:]...).Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
+
+// RegisterSecretResolver registers a SecretResolver for values like '<scheme>://<reference>'.
+// An existing resolver of the scheme (including the built-in ones) will be overwritten.
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretResolverMutex.Lock()
+	defer secretResolverMutex.Unlock()
+
+	secretResolvers[scheme] = resolver
+}
+
+// UnregisterSecretResolver removes the SecretResolver of a scheme, including the built-in ones.
+func UnregisterSecretResolver(scheme string) {
+	secretResolverMutex.Lock()
+	defer secretResolverMutex.Unlock()
+
+	delete(secretResolvers, scheme)
+}
+
+// ResetSecretResolvers restores the built-in secret resolvers (file and env).
+func ResetSecretResolvers() {
+	secretResolverMutex.Lock()
+	defer secretResolverMutex.Unlock()
+
+	secretResolvers = copySecretResolvers(defaultSecretResolvers)
+}
+
+// SecretSchemes returns the sorted schemes of all registered secret resolvers.
+func SecretSchemes() []string {
+	secretResolverMutex.RLock()
+	defer secretResolverMutex.RUnlock()
+
+	schemes := make([]string, 0, len(secretResolvers))
+	for scheme := range secretResolvers {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+
+	return schemes
+}
+
+// UseSecretResolution enables the resolution of secret references in string fields after all load items have been
+// loaded. Values with the scheme of a registered SecretResolver (e.g. 'file:///run/secrets/db_password' or
+// 'env://OTHER_VAR') are replaced by the resolved secret. Values with other schemes (e.g. 'https://') are kept.
+// Note that every 'file://' value is replaced, including file URLs which are meant as plain values. Use
+// UnregisterSecretResolver("file") if the config contains such URLs.
+func (c *ConfigLoader) UseSecretResolution() *ConfigLoader {
+	c.SecretResolution = true
+	return c
+}
+
+// SecretError is returned by LoadInto when the secret reference of a field could not be resolved.
+type SecretError struct {
+	Field  string
+	Scheme string
+	Err    error
+}
+
+func (e *SecretError) Error() string {
+	return fmt.Sprintf("secret of field '%s' (scheme '%s'): %s", e.Field, e.Scheme, e.Err)
+}
+
+func (e *SecretError) Unwrap() error {
+	return e.Err
+}
+
+func resolveSecrets(cfg interface{}) error {
+	for _, field := range collectStructFields(cfg) {
+		if field.value.Kind() != reflect.String || !field.value.CanSet() {
+			continue
+		}
+
+		scheme, reference, ok := splitSecretReference(field.value.String())
+		if !ok {
+			continue
+		}
+
+		resolver, ok := secretResolverForScheme(scheme)
+		if !ok {
+			continue
+		}
+
+		secret, err := resolver(reference)
+		if err != nil {
+			return &SecretError{Field: field.path, Scheme: scheme, Err: err}
+		}
+
+		field.value.SetString(secret)
+		field.mapEntry.store()
+	}
+
+	return nil
+}
+
+func splitSecretReference(value string) (string, string, bool) {
+	separatorIndex := strings.Index(value, secretSchemeSeparator)
+	if separatorIndex <= 0 {
+		return "", "", false
+	}
+
+	return value[:separatorIndex], value[separatorIndex+len(secretSchemeSeparator):], true
+}
+
+func secretResolverForScheme(scheme string) (SecretResolver, bool) {
+	secretResolverMutex.RLock()
+	defer secretResolverMutex.RUnlock()
+
+	resolver, ok := secretResolvers[scheme]
+	return resolver, ok
+}
+
+func copySecretResolvers(source map[string]SecretResolver) map[string]SecretResolver {
+	target := make(map[string]SecretResolver, len(source))
+	for scheme, resolver := range source {
+		target[scheme] = resolver
+	}
+
+	return target
+}
@@ -0,0 +1,157 @@
+package yetenv
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type secretTestConfig struct {
+	Database struct {
+		Password string `yaml:"password"`
+	} `yaml:"database"`
+	APIKey  string `yaml:"api_key"`
+	Command string `yaml:"command"`
+	URL     string `yaml:"url"`
+	Port    int    `yaml:"port"`
+}
+
+func TestConfigLoader_UseSecretResolution(t *testing.T) {
+	t.Cleanup(ResetSecretResolvers)
+
+	loadSecrets := func(content string) (secretTestConfig, error) {
+		c := secretTestConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			UseSecretResolution().
+			LoadFromBytes([]byte(content), YAML).
+			LoadInto(&c)
+
+		return c, err
+	}
+
+	t.Run("should resolve built-in secret references", func(t *testing.T) {
+		RegisterSecretResolver("exec", ExecSecretResolver)
+		defer ResetSecretResolvers()
+
+		secretFile := filepath.Join(t.TempDir(), "db_password")
+		require.NoError(t, ioutil.WriteFile(secretFile, []byte("s3cr3t\n"), 0o600))
+
+		require.NoError(t, os.Setenv("SECRET_TEST_API_KEY", "api-key"))
+		defer os.Unsetenv("SECRET_TEST_API_KEY")
+
+		c, err := loadSecrets(strings.Join([]string{
+			"database:",
+			"  password: file://" + filepath.ToSlash(secretFile),
+			"api_key: env://SECRET_TEST_API_KEY",
+			"command: exec://go env GOOS",
+			"url: https://example.com",
+			"port: 8080",
+		}, "\n"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "s3cr3t", c.Database.Password)
+		assert.Equal(t, "api-key", c.APIKey)
+		assert.Equal(t, runtime.GOOS, c.Command)
+		assert.Equal(t, "https://example.com", c.URL)
+		assert.Equal(t, 8080, c.Port)
+	})
+
+	t.Run("should not run commands without registered exec resolver", func(t *testing.T) {
+		c, err := loadSecrets("command: exec://go env GOOS")
+		require.NoError(t, err)
+
+		assert.Equal(t, "exec://go env GOOS", c.Command)
+	})
+
+	t.Run("should resolve secret references of custom schemes", func(t *testing.T) {
+		RegisterSecretResolver("vault", func(reference string) (string, error) {
+			return "vault:" + reference, nil
+		})
+		defer ResetSecretResolvers()
+
+		c, err := loadSecrets("api_key: vault://secret/data/app")
+		require.NoError(t, err)
+
+		assert.Equal(t, "vault:secret/data/app", c.APIKey)
+	})
+
+	t.Run("should resolve secret references of pointers, slices and maps of structs", func(t *testing.T) {
+		require.NoError(t, os.Setenv("SECRET_TEST_API_KEY", "api-key"))
+		defer os.Unsetenv("SECRET_TEST_API_KEY")
+
+		type credentials struct {
+			APIKey string `yaml:"api_key"`
+		}
+
+		c := struct {
+			Primary  *credentials           `yaml:"primary"`
+			Replicas []credentials          `yaml:"replicas"`
+			ByName   map[string]credentials `yaml:"by_name"`
+		}{}
+
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			UseSecretResolution().
+			LoadFromBytes([]byte(strings.Join([]string{
+				"primary:",
+				"  api_key: env://SECRET_TEST_API_KEY",
+				"replicas:",
+				"  - api_key: env://SECRET_TEST_API_KEY",
+				"by_name:",
+				"  backup:",
+				"    api_key: env://SECRET_TEST_API_KEY",
+			}, "\n")), YAML).
+			LoadInto(&c)
+		require.NoError(t, err)
+
+		assert.Equal(t, "api-key", c.Primary.APIKey)
+		assert.Equal(t, "api-key", c.Replicas[0].APIKey)
+		assert.Equal(t, "api-key", c.ByName["backup"].APIKey)
+	})
+
+	t.Run("should return error which names the field", func(t *testing.T) {
+		_ = os.Unsetenv("SECRET_TEST_API_KEY")
+
+		_, err := loadSecrets("database:\n  password: env://SECRET_TEST_API_KEY")
+
+		var secretErr *SecretError
+		require.True(t, errors.As(err, &secretErr))
+		assert.Equal(t, "Database.Password", secretErr.Field)
+		assert.True(t, errors.Is(err, ErrSecretNotFound))
+		assert.EqualError(t, err, "secret of field 'Database.Password' (scheme 'env'): secret not found: "+
+			"environment variable 'SECRET_TEST_API_KEY' is not set")
+	})
+
+	t.Run("should not resolve secret references without UseSecretResolution", func(t *testing.T) {
+		c := secretTestConfig{}
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			LoadFromBytes([]byte("api_key: env://SECRET_TEST_API_KEY"), YAML).
+			LoadInto(&c)
+		require.NoError(t, err)
+
+		assert.Equal(t, "env://SECRET_TEST_API_KEY", c.APIKey)
+	})
+}
+
+func TestSecretSchemes(t *testing.T) {
+	t.Cleanup(ResetSecretResolvers)
+
+	assert.Equal(t, []string{"env", "file"}, SecretSchemes())
+
+	UnregisterSecretResolver("file")
+	RegisterSecretResolver("exec", ExecSecretResolver)
+	RegisterSecretResolver("vault", EnvSecretResolver)
+	assert.Equal(t, []string{"env", "exec", "vault"}, SecretSchemes())
+
+	ResetSecretResolvers()
+	assert.Equal(t, []string{"env", "file"}, SecretSchemes())
+}
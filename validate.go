@@ -0,0 +1,281 @@
+package yetenv
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidConfig = errors.New("config is invalid")
+)
+
+// Validator can be implemented by config structs. Validate is called by LoadInto after all load items are loaded,
+// if enabled by UseValidation.
+type Validator interface {
+	Validate() error
+}
+
+// FieldError describes a config field which violates a validation rule.
+type FieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError aggregates all validation failures of a config. It matches ErrInvalidConfig and every aggregated
+// error with errors.Is and errors.As.
+type ValidationError struct {
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf("%s: %s", ErrInvalidConfig, strings.Join(messages, "; "))
+}
+
+// Is loops over the aggregated errors, because errors.Is only unwraps multiple errors since Go 1.20.
+func (e *ValidationError) Is(target error) bool {
+	if target == ErrInvalidConfig {
+		return true
+	}
+
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As loops over the aggregated errors, because errors.As only unwraps multiple errors since Go 1.20.
+func (e *ValidationError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateConfig validates a config struct by the rules of its `validate` tags and by its Validate method when it
+// implements Validator. All failures are returned as one *ValidationError.
+//
+// Supported rules (comma separated, e.g. `validate:"required,min=1,max=10"`):
+//
+//	required      value must not be the zero value
+//	min=<n>       minimum for numbers and durations, minimum length for strings, slices and maps
+//	max=<n>       maximum for numbers and durations, maximum length for strings, slices and maps
+//	oneof=<a b>   value must be one of the space separated values
+//	url           value must be an absolute URL
+//	port          value must be a port between 1 and 65535
+//
+// The rules oneof and url are skipped for empty values, so optional fields can use them. All rules except required
+// are skipped for nil pointers, otherwise they are applied to the pointed value.
+func ValidateConfig(cfg interface{}) error {
+	return ValidateConfigForEnvironment(cfg, "")
+}
+
+// ValidateConfigForEnvironment works like ValidateConfig and additionally checks the `required-in` tags against the
+// provided environment. A field tagged with `required-in:"production,staging"` must not be the zero value in
+// Production and Staging. The environments of the tag are parsed by ParseEnvironment.
+func ValidateConfigForEnvironment(cfg interface{}, environment Environment) error {
+	return validateConfig(cfg, environment, true)
+}
+
+// UseValidation enables the validation of the loaded config by ValidateConfigForEnvironment as final phase of
+// LoadInto. Without it, only the `required-in` tags are checked, so `validate` tags of other validation packages
+// don't break the load process.
+func (c *ConfigLoader) UseValidation() *ConfigLoader {
+	c.Validation = true
+	return c
+}
+
+func validateConfig(cfg interface{}, environment Environment, withRules bool) error {
+	var errs []error
+
+	for _, field := range collectStructFields(cfg) {
+		if withRules {
+			errs = append(errs, validateField(field)...)
+		}
+
+		if err := validateRequiredIn(field, environment); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if validator, ok := cfg.(Validator); ok && withRules {
+		if err := validator.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+
+	return nil
+}
+
+func validateField(field structField) []error {
+	tag, ok := field.structField.Tag.Lookup("validate")
+	if !ok || tag == "" {
+		return nil
+	}
+
+	var errs []error
+	for _, rule := range strings.Split(tag, ",") {
+		ruleName, ruleParam := rule, ""
+		if index := strings.Index(rule, "="); index >= 0 {
+			ruleName, ruleParam = rule[:index], rule[index+1:]
+		}
+
+		message := validateRule(field.value, ruleName, ruleParam)
+		if message != "" {
+			errs = append(errs, &FieldError{Field: field.path, Rule: ruleName, Message: message})
+		}
+	}
+
+	return errs
+}
+
+func validateRequiredIn(field structField, environment Environment) error {
+	tag, ok := field.structField.Tag.Lookup("required-in")
+	if !ok || tag == "" {
+		return nil
+	}
+
+	for _, requiredEnvironmentRaw := range strings.Split(tag, ",") {
+		requiredEnvironment, err := ParseEnvironment(requiredEnvironmentRaw)
+		if err != nil {
+			return &FieldError{
+				Field:   field.path,
+				Rule:    "required-in",
+				Message: fmt.Sprintf("unknown environment '%s' in required-in", requiredEnvironmentRaw),
+			}
+		}
+
+		if requiredEnvironment == environment && field.value.IsZero() {
+			return &FieldError{
+				Field:   field.path,
+				Rule:    "required-in",
+				Message: fmt.Sprintf("is required in environment '%s'", environment),
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateRule(value reflect.Value, ruleName string, ruleParam string) string {
+	if ruleName == "required" {
+		if value.IsZero() {
+			return "is required"
+		}
+
+		return ""
+	}
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return ""
+		}
+
+		value = value.Elem()
+	}
+
+	if (ruleName == "oneof" || ruleName == "url") && value.IsZero() {
+		return ""
+	}
+
+	switch ruleName {
+	case "min", "max":
+		return validateBound(value, ruleName, ruleParam)
+	case "oneof":
+		for _, allowedValue := range strings.Fields(ruleParam) {
+			if fmt.Sprint(value.Interface()) == allowedValue {
+				return ""
+			}
+		}
+
+		return fmt.Sprintf("must be one of [%s]", ruleParam)
+	case "url":
+		parsedURL, err := url.Parse(fmt.Sprint(value.Interface()))
+		if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
+			return "must be a valid url"
+		}
+
+		return ""
+	case "port":
+		port, err := strconv.Atoi(fmt.Sprint(value.Interface()))
+		if err != nil || port < 1 || port > 65535 {
+			return "must be a port between 1 and 65535"
+		}
+
+		return ""
+	}
+
+	return fmt.Sprintf("unknown validation rule '%s'", ruleName)
+}
+
+func validateBound(value reflect.Value, ruleName string, ruleParam string) string {
+	actual, bound, err := boundValues(value, ruleParam)
+	if err != nil {
+		return fmt.Sprintf("invalid parameter for rule '%s': %s", ruleName, err)
+	}
+
+	subject := "must be"
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		subject = "length must be"
+	}
+
+	if ruleName == "min" && actual < bound {
+		return fmt.Sprintf("%s at least %s", subject, ruleParam)
+	}
+
+	if ruleName == "max" && actual > bound {
+		return fmt.Sprintf("%s at most %s", subject, ruleParam)
+	}
+
+	return ""
+}
+
+func boundValues(value reflect.Value, ruleParam string) (float64, float64, error) {
+	if value.Type() == reflect.TypeOf(time.Duration(0)) {
+		bound, err := time.ParseDuration(ruleParam)
+		return float64(value.Int()), float64(bound), err
+	}
+
+	bound, err := strconv.ParseFloat(ruleParam, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), bound, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), bound, nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), bound, nil
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return float64(value.Len()), bound, nil
+	}
+
+	return 0, 0, fmt.Errorf("unsupported type %s", value.Type())
+}
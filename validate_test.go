@@ -0,0 +1,266 @@
+package yetenv
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validateTestConfig struct {
+	Database struct {
+		Host string `validate:"required"`
+		Port int    `validate:"port"`
+	}
+	Name     string        `validate:"min=3,max=5"`
+	Workers  int           `validate:"min=1,max=10"`
+	Timeout  time.Duration `validate:"max=1m"`
+	LogLevel string        `validate:"oneof=debug info error"`
+	Endpoint string        `validate:"url"`
+	Tags     []string      `validate:"max=2"`
+}
+
+var errValidatorTest = errors.New("name must not be 'invalid'")
+
+type validatorTestConfig struct {
+	Name string `validate:"required"`
+}
+
+func (c validatorTestConfig) Validate() error {
+	if c.Name == "invalid" {
+		return errValidatorTest
+	}
+
+	return nil
+}
+
+func TestValidateConfig(t *testing.T) {
+	t.Run("should return nil for valid config", func(t *testing.T) {
+		c := validateTestConfig{
+			Name:     "yet",
+			Workers:  5,
+			Timeout:  time.Second,
+			LogLevel: "info",
+			Endpoint: "https://example.com/api",
+			Tags:     []string{"a"},
+		}
+		c.Database.Host = "localhost"
+		c.Database.Port = 5432
+
+		assert.NoError(t, ValidateConfig(&c))
+	})
+
+	t.Run("should aggregate all field errors", func(t *testing.T) {
+		c := validateTestConfig{
+			Name:     "yetenv",
+			Workers:  11,
+			Timeout:  time.Hour,
+			LogLevel: "trace",
+			Endpoint: "example.com",
+			Tags:     []string{"a", "b", "c"},
+		}
+		c.Database.Port = 70000
+
+		err := ValidateConfig(&c)
+
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		assert.True(t, errors.Is(err, ErrInvalidConfig))
+
+		var fieldErr *FieldError
+		require.True(t, errors.As(err, &fieldErr))
+		assert.Equal(t, "Database.Host", fieldErr.Field)
+
+		expectedMessages := []string{
+			"Database.Host: is required",
+			"Database.Port: must be a port between 1 and 65535",
+			"Name: length must be at most 5",
+			"Workers: must be at most 10",
+			"Timeout: must be at most 1m",
+			"LogLevel: must be one of [debug info error]",
+			"Endpoint: must be a valid url",
+			"Tags: length must be at most 2",
+		}
+
+		actualMessages := make([]string, 0, len(validationErr.Errors))
+		for _, fieldErr := range validationErr.Errors {
+			actualMessages = append(actualMessages, fieldErr.Error())
+		}
+
+		assert.Equal(t, expectedMessages, actualMessages)
+	})
+
+	t.Run("should apply bounds and port to zero values", func(t *testing.T) {
+		c := validateTestConfig{Name: "yet"}
+		c.Database.Host = "localhost"
+
+		err := ValidateConfig(&c)
+		assert.EqualError(t, err, "config is invalid: Database.Port: must be a port between 1 and 65535; "+
+			"Workers: must be at least 1")
+	})
+
+	t.Run("should skip rules for nil pointers", func(t *testing.T) {
+		type pointerConfig struct {
+			Retries  *int    `validate:"min=1"`
+			LogLevel *string `validate:"oneof=debug info"`
+		}
+
+		assert.NoError(t, ValidateConfig(&pointerConfig{}))
+
+		retries, logLevel := 0, "trace"
+		err := ValidateConfig(&pointerConfig{Retries: &retries, LogLevel: &logLevel})
+		assert.EqualError(t, err, "config is invalid: Retries: must be at least 1; LogLevel: must be one of [debug info]")
+	})
+
+	t.Run("should return error for unknown rule", func(t *testing.T) {
+		c := struct {
+			Name string `validate:"email"`
+		}{Name: "yetenv"}
+
+		err := ValidateConfig(&c)
+		assert.EqualError(t, err, "config is invalid: Name: unknown validation rule 'email'")
+	})
+
+	t.Run("should validate fields of pointers, slices and maps of structs", func(t *testing.T) {
+		type replica struct {
+			Host string `validate:"required"`
+		}
+
+		c := struct {
+			Primary  *replica
+			Replicas []replica `validate:"min=1"`
+			ByName   map[string]replica
+		}{
+			Primary:  &replica{},
+			Replicas: []replica{{Host: "r1"}, {}},
+			ByName:   map[string]replica{"backup": {}},
+		}
+
+		err := ValidateConfig(&c)
+		assert.EqualError(t, err, "config is invalid: Primary.Host: is required; Replicas[1].Host: is required; "+
+			"ByName[backup].Host: is required")
+
+		c.Replicas = []replica{}
+		err = ValidateConfig(&c)
+		assert.Contains(t, err.Error(), "Replicas: length must be at least 1")
+	})
+
+	t.Run("should call validator and aggregate its error", func(t *testing.T) {
+		assert.NoError(t, ValidateConfig(&validatorTestConfig{Name: "valid"}))
+
+		err := ValidateConfig(&validatorTestConfig{Name: "invalid"})
+		assert.EqualError(t, err, "config is invalid: name must not be 'invalid'")
+		assert.True(t, errors.Is(err, errValidatorTest))
+
+		err = ValidateConfig(&validatorTestConfig{})
+		assert.EqualError(t, err, "config is invalid: Name: is required")
+	})
+}
+
+func TestConfigLoader_LoadInto_Validation(t *testing.T) {
+	t.Run("should validate config with UseValidation", func(t *testing.T) {
+		resetEnv()
+
+		c := struct {
+			LastFile string `env:"LAST_FILE" validate:"oneof=custom-load"`
+			Develop  bool   `env:"DEVELOP" validate:"required"`
+		}{}
+
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			UseValidation().
+			LoadFromFile("./testdata/custom-load.env").
+			LoadInto(&c)
+
+		assert.EqualError(t, err, "config is invalid: Develop: is required")
+	})
+
+	t.Run("should not validate config without UseValidation", func(t *testing.T) {
+		resetEnv()
+
+		c := struct {
+			LastFile string `env:"LAST_FILE" validate:"email"`
+			Develop  bool   `env:"DEVELOP" validate:"required"`
+		}{}
+
+		err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			LoadFromFile("./testdata/custom-load.env").
+			LoadInto(&c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "custom-load", c.LastFile)
+	})
+}
+
+func TestValidateConfigForEnvironment(t *testing.T) {
+	type requiredInConfig struct {
+		SentryDSN string `required-in:"production,stg"`
+		APIKey    string `required-in:"production"`
+		Debug     bool
+	}
+
+	t.Run("should not require fields in other environments", func(t *testing.T) {
+		assert.NoError(t, ValidateConfigForEnvironment(&requiredInConfig{}, Develop))
+	})
+
+	t.Run("should report all missing fields together", func(t *testing.T) {
+		err := ValidateConfigForEnvironment(&requiredInConfig{}, Production)
+
+		expectedErr := "config is invalid: SentryDSN: is required in environment 'production'; " +
+			"APIKey: is required in environment 'production'"
+
+		assert.EqualError(t, err, expectedErr)
+	})
+
+	t.Run("should accept aliases in tag", func(t *testing.T) {
+		err := ValidateConfigForEnvironment(&requiredInConfig{}, Staging)
+		assert.EqualError(t, err, "config is invalid: SentryDSN: is required in environment 'staging'")
+
+		assert.NoError(t, ValidateConfigForEnvironment(&requiredInConfig{SentryDSN: "dsn"}, Staging))
+	})
+
+	t.Run("should report unknown environments in tag", func(t *testing.T) {
+		c := struct {
+			Name string `required-in:"prodution"`
+		}{}
+
+		err := ValidateConfigForEnvironment(&c, Production)
+		assert.EqualError(t, err, "config is invalid: Name: unknown environment 'prodution' in required-in")
+	})
+}
+
+func TestConfigLoader_LoadInto_RequiredIn(t *testing.T) {
+	type requiredInConfig struct {
+		Production bool   `env:"PROD"`
+		SentryDSN  string `env:"SENTRY_DSN" required-in:"production"`
+	}
+
+	t.Run("should return error for missing field in production", func(t *testing.T) {
+		resetEnv()
+
+		c := requiredInConfig{}
+		err := NewConfigLoader().
+			UseLoadPath("./testdata").
+			UseEnvironment(Production).
+			UseDefaultLoadBehavior().
+			LoadInto(&c)
+
+		assert.EqualError(t, err, "config is invalid: SentryDSN: is required in environment 'production'")
+	})
+
+	t.Run("should not return error for missing field in develop", func(t *testing.T) {
+		resetEnv()
+
+		c := requiredInConfig{}
+		err := NewConfigLoader().
+			UseLoadPath("./testdata").
+			UseEnvironment(Develop).
+			UseDefaultLoadBehavior().
+			LoadInto(&c)
+
+		assert.NoError(t, err)
+	})
+}
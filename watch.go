@@ -0,0 +1,403 @@
+package yetenv
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultWatchInterval is the default interval in which a Watcher polls the loaded config files.
+const DefaultWatchInterval = 5 * time.Second
+
+// ReloadFunc is called by a Watcher after the config has been reloaded successfully.
+// oldCfg and newCfg are pointers to the previous and the reloaded config struct. It is called by the polling
+// goroutine (or by the caller of Reload), so the next reload waits until it returns.
+type ReloadFunc func(oldCfg, newCfg interface{})
+
+// ReloadErrorFunc is called by a Watcher when the config could not be reloaded. The previous config stays active.
+type ReloadErrorFunc func(err error)
+
+// UseWatchInterval can be used to change the interval in which a Watcher polls the loaded config files.
+// It defaults to DefaultWatchInterval.
+func (c *ConfigLoader) UseWatchInterval(interval time.Duration) *ConfigLoader {
+	c.WatchInterval = interval
+	return c
+}
+
+// OnReload registers a ReloadFunc which is called by the Watcher after every successful reload.
+func (c *ConfigLoader) OnReload(reloadFunc ReloadFunc) *ConfigLoader {
+	c.reloadFuncs = append(c.reloadFuncs, reloadFunc)
+	return c
+}
+
+// OnReloadError registers a ReloadErrorFunc which is called by the Watcher after every failed reload.
+func (c *ConfigLoader) OnReloadError(reloadErrorFunc ReloadErrorFunc) *ConfigLoader {
+	c.reloadErrorFuncs = append(c.reloadErrorFuncs, reloadErrorFunc)
+	return c
+}
+
+// Watch will finish the ConfigLoader like LoadInto and watches the config files afterwards. The provided config
+// struct must be a pointer to a struct. When a config file of the load order changes or is created, the load order is
+// executed again into a new config struct, which is available by Config of the returned Watcher.
+// The files are polled, so symlink swaps (e.g. '..data' of Kubernetes ConfigMaps) are detected as well.
+// Reloads run on a copy of the ConfigLoader, so Provenance and ResolvedPaths of the ConfigLoader keep describing the
+// initial load, while the Watcher provides them for the current config. The ConfigLoader must not be changed while it
+// is watched.
+func (c *ConfigLoader) Watch(cfg interface{}) (*Watcher, error) {
+	return c.watch(cfg, nil)
+}
+
+// watch calls storeFunc (if any) with every loaded config, including the initial one.
+func (c *ConfigLoader) watch(cfg interface{}, storeFunc func(cfg interface{})) (*Watcher, error) {
+	cfgValue := reflect.ValueOf(cfg)
+	if cfgValue.Kind() != reflect.Ptr || cfgValue.Elem().Kind() != reflect.Struct {
+		return nil, ErrNoConfigStruct
+	}
+
+	if err := c.LoadInto(cfg); err != nil {
+		return nil, err
+	}
+
+	if storeFunc != nil {
+		storeFunc(cfg)
+	}
+
+	interval := c.WatchInterval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	dotenvOriginals := make(map[string]*string, len(c.dotenvOriginals))
+	dotenvVariables := make(map[string]string, len(c.dotenvOriginals))
+	for name, original := range c.dotenvOriginals {
+		dotenvOriginals[name] = original
+		dotenvVariables[name] = os.Getenv(name)
+	}
+
+	watcher := &Watcher{
+		configLoader:    c,
+		configType:      cfgValue.Elem().Type(),
+		current:         cfg,
+		provenance:      c.Provenance(),
+		resolvedPaths:   c.ResolvedPaths(),
+		dotenvOriginals: dotenvOriginals,
+		dotenvVariables: dotenvVariables,
+		storeFunc:       storeFunc,
+		files:           c.watchedFileStates(),
+		stop:            make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+
+	go watcher.poll(interval)
+
+	return watcher, nil
+}
+
+// Watcher reloads a config when its config files change. It is created by Watch of the ConfigLoader.
+type Watcher struct {
+	configLoader *ConfigLoader
+	configType   reflect.Type
+	reloadMutex  sync.Mutex
+	currentMutex sync.RWMutex
+	current      interface{}
+	// provenance and resolvedPaths belong to the current config and are guarded by currentMutex.
+	provenance    Provenance
+	resolvedPaths []string
+	// dotenvOriginals are the OS environment values of all variables which have been set by dotenv files.
+	dotenvOriginals map[string]*string
+	dotenvVariables map[string]string
+	// runningCallbacks counts the running ReloadFunc and ReloadErrorFunc calls, so they can call Stop.
+	runningCallbacks int32
+	storeFunc        func(cfg interface{})
+	files            map[string]fileState
+	stopOnce         sync.Once
+	stop             chan struct{}
+	done             chan struct{}
+}
+
+// Config returns a pointer to the current config struct. The config struct is replaced on every successful reload,
+// so it must not be changed.
+func (w *Watcher) Config() interface{} {
+	w.currentMutex.RLock()
+	defer w.currentMutex.RUnlock()
+
+	return w.current
+}
+
+// Provenance returns the source of every field of the current config (see Provenance of the ConfigLoader).
+func (w *Watcher) Provenance() Provenance {
+	w.currentMutex.RLock()
+	defer w.currentMutex.RUnlock()
+
+	return w.provenance
+}
+
+// ResolvedPaths returns the paths of all config files which were loaded into the current config in load order.
+func (w *Watcher) ResolvedPaths() []string {
+	w.currentMutex.RLock()
+	defer w.currentMutex.RUnlock()
+
+	return w.resolvedPaths
+}
+
+// Reload executes the load order into a new config struct, regardless of changes of the config files.
+// The registered ReloadFunc or ReloadErrorFunc are called like for a reload caused by a changed file.
+func (w *Watcher) Reload() error {
+	w.reloadMutex.Lock()
+	defer w.reloadMutex.Unlock()
+
+	return w.reload()
+}
+
+// Stop stops polling the config files. It waits until a running reload is finished, unless it is called by a
+// ReloadFunc or ReloadErrorFunc, which would wait for itself otherwise.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+
+	if atomic.LoadInt32(&w.runningCallbacks) > 0 {
+		return
+	}
+
+	<-w.done
+}
+
+func (w *Watcher) poll(interval time.Duration) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.reloadIfChanged()
+		}
+	}
+}
+
+func (w *Watcher) reloadIfChanged() {
+	w.reloadMutex.Lock()
+	defer w.reloadMutex.Unlock()
+
+	select {
+	case <-w.stop:
+		return
+	default:
+	}
+
+	changed := false
+	for file, state := range w.files {
+		currentState := w.configLoader.fileState(file)
+		if !currentState.equal(state) {
+			w.files[file] = currentState
+			changed = true
+		}
+	}
+
+	if changed {
+		_ = w.reload()
+	}
+}
+
+// reload collects the variables of the dotenv files first and applies only the differences to the OS environment,
+// so variables which are kept are never unset. The variables of the previous load are restored when the reload fails.
+func (w *Watcher) reload() error {
+	newCfg, loader, err := w.load()
+	if err != nil {
+		w.runCallbacks(func() {
+			for _, reloadErrorFunc := range w.configLoader.reloadErrorFuncs {
+				reloadErrorFunc(err)
+			}
+		})
+
+		return err
+	}
+
+	w.files = loader.watchedFileStates()
+
+	w.currentMutex.Lock()
+	oldCfg := w.current
+	w.current = newCfg
+	w.provenance = loader.Provenance()
+	w.resolvedPaths = loader.ResolvedPaths()
+	w.currentMutex.Unlock()
+
+	if w.storeFunc != nil {
+		w.storeFunc(newCfg)
+	}
+
+	w.runCallbacks(func() {
+		for _, reloadFunc := range w.configLoader.reloadFuncs {
+			reloadFunc(oldCfg, newCfg)
+		}
+	})
+
+	return nil
+}
+
+// load executes the load order by copies of the ConfigLoader, so the ConfigLoader can be read while reloading.
+func (w *Watcher) load() (interface{}, *ConfigLoader, error) {
+	collector := *w.configLoader
+	collector.dotenvOriginals = w.dotenvOriginals
+
+	dotenvVariables, err := collector.collectDotenvVariables(reflect.New(w.configType).Interface())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previousDotenvVariables := w.dotenvVariables
+	if err := w.applyDotenvVariables(dotenvVariables); err != nil {
+		_ = w.applyDotenvVariables(previousDotenvVariables)
+		return nil, nil, err
+	}
+
+	loader := *w.configLoader
+	loader.dotenvOriginals = nil
+
+	newCfg := reflect.New(w.configType).Interface()
+	if err := loader.LoadInto(newCfg); err != nil {
+		_ = w.applyDotenvVariables(previousDotenvVariables)
+		return nil, nil, err
+	}
+
+	return newCfg, &loader, nil
+}
+
+// applyDotenvVariables changes the OS environment from the variables of the previous load to the provided ones.
+// Variables which are not provided anymore get their original value back.
+func (w *Watcher) applyDotenvVariables(variables map[string]string) error {
+	for name, value := range variables {
+		if _, ok := w.dotenvOriginals[name]; !ok {
+			var original *string
+			if originalValue, ok := os.LookupEnv(name); ok {
+				original = &originalValue
+			}
+
+			w.dotenvOriginals[name] = original
+		}
+
+		if currentValue, ok := os.LookupEnv(name); ok && currentValue == value {
+			continue
+		}
+
+		if err := os.Setenv(name, value); err != nil {
+			return err
+		}
+	}
+
+	for name, original := range w.dotenvOriginals {
+		if _, ok := variables[name]; ok {
+			continue
+		}
+
+		if original == nil {
+			_ = os.Unsetenv(name)
+		} else {
+			_ = os.Setenv(name, *original)
+		}
+
+		delete(w.dotenvOriginals, name)
+	}
+
+	w.dotenvVariables = variables
+	return nil
+}
+
+func (w *Watcher) runCallbacks(callbacks func()) {
+	atomic.AddInt32(&w.runningCallbacks, 1)
+	defer atomic.AddInt32(&w.runningCallbacks, -1)
+
+	callbacks()
+}
+
+// collectDotenvVariables executes the load order like LoadInto, but the variables of dotenv files are returned
+// instead of being written into the OS environment.
+func (c *ConfigLoader) collectDotenvVariables(cfg interface{}) (map[string]string, error) {
+	dotenvVariables := map[string]string{}
+	c.pendingDotenvVariables = dotenvVariables
+	defer func() {
+		c.pendingDotenvVariables = nil
+	}()
+
+	if err := c.loadItems(cfg); err != nil {
+		return nil, err
+	}
+
+	return dotenvVariables, nil
+}
+
+// fileState is compared between two polls. The target of a symlink is part of the state, because the modification
+// time of a swapped file is not necessarily newer.
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+	target  string
+}
+
+func (s fileState) equal(other fileState) bool {
+	return s.exists == other.exists && s.modTime.Equal(other.modTime) && s.size == other.size && s.target == other.target
+}
+
+// watchedFileStates returns the states of all candidate files of the load order, so files which are created later
+// (e.g. a file of a closer search path or a '.local' override) cause a reload as well.
+func (c *ConfigLoader) watchedFileStates() map[string]fileState {
+	states := map[string]fileState{}
+	for _, file := range c.resolvedPaths {
+		states[file] = c.fileState(file)
+	}
+
+	for _, loadItem := range c.loadOrder {
+		if loadItem.source != nil {
+			continue
+		}
+
+		if loadItem.conditionFunc != nil && !loadItem.conditionFunc(c, c.Environment) {
+			continue
+		}
+
+		if len(loadItem.candidates) == 0 {
+			states[loadItem.file] = c.fileState(loadItem.file)
+			continue
+		}
+
+		for _, searchPathCandidates := range loadItem.candidates {
+			for _, candidate := range searchPathCandidates {
+				states[candidate] = c.fileState(candidate)
+			}
+		}
+	}
+
+	return states
+}
+
+func (c *ConfigLoader) fileState(filename string) fileState {
+	if c.FileSystem != nil {
+		info, err := fs.Stat(c.FileSystem, fileSystemPath(filename))
+		if err != nil {
+			return fileState{}
+		}
+
+		return fileState{exists: true, modTime: info.ModTime(), size: info.Size()}
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return fileState{}
+	}
+
+	target, err := filepath.EvalSymlinks(filename)
+	if err != nil {
+		target = filename
+	}
+
+	return fileState{exists: true, modTime: info.ModTime(), size: info.Size(), target: target}
+}
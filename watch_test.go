@@ -0,0 +1,359 @@
+package yetenv
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type watchTestConfig struct {
+	Name string `yaml:"name"`
+}
+
+type watchTestReload struct {
+	oldCfg *watchTestConfig
+	newCfg *watchTestConfig
+}
+
+func TestConfigLoader_Watch(t *testing.T) {
+	writeConfigFile := func(t *testing.T, filename string, content string, modTime time.Time) {
+		require.NoError(t, ioutil.WriteFile(filename, []byte(content), 0o644))
+		require.NoError(t, os.Chtimes(filename, modTime, modTime))
+	}
+
+	watchConfigFile := func(t *testing.T, filename string) (*Watcher, *watchTestConfig, chan watchTestReload, chan error) {
+		reloads := make(chan watchTestReload, 10)
+		reloadErrors := make(chan error, 10)
+
+		c := watchTestConfig{}
+		watcher, err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			UseWatchInterval(10 * time.Millisecond).
+			LoadFromRequiredFile(filename).
+			OnReload(func(oldCfg, newCfg interface{}) {
+				reloads <- watchTestReload{oldCfg: oldCfg.(*watchTestConfig), newCfg: newCfg.(*watchTestConfig)}
+			}).
+			OnReloadError(func(err error) {
+				reloadErrors <- err
+			}).
+			Watch(&c)
+		require.NoError(t, err)
+		t.Cleanup(watcher.Stop)
+
+		return watcher, &c, reloads, reloadErrors
+	}
+
+	modTime := time.Now().Add(-time.Hour)
+
+	t.Run("should return error for non struct pointer", func(t *testing.T) {
+		c := watchTestConfig{}
+		_, err := NewConfigLoader().UseCustomLoadBehavior().Watch(c)
+		assert.Equal(t, ErrNoConfigStruct, err)
+	})
+
+	t.Run("should return error of initial load", func(t *testing.T) {
+		c := watchTestConfig{}
+		_, err := NewConfigLoader().
+			UseCustomLoadBehavior().
+			LoadFromRequiredFile(filepath.Join(t.TempDir(), "cfg.yaml")).
+			Watch(&c)
+		assert.True(t, errors.Is(err, ErrConfigFileNotFound))
+	})
+
+	t.Run("should reload config when a file changes", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "cfg.yaml")
+		writeConfigFile(t, filename, "name: first", modTime)
+
+		watcher, c, reloads, _ := watchConfigFile(t, filename)
+		assert.Equal(t, "first", c.Name)
+		assert.Equal(t, c, watcher.Config())
+
+		writeConfigFile(t, filename, "name: second", modTime.Add(time.Minute))
+
+		select {
+		case reload := <-reloads:
+			assert.Equal(t, "first", reload.oldCfg.Name)
+			assert.Equal(t, "second", reload.newCfg.Name)
+			assert.Equal(t, reload.newCfg, watcher.Config())
+		case <-time.After(5 * time.Second):
+			t.Fatal("config was not reloaded")
+		}
+	})
+
+	t.Run("should keep previous config when reload fails", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "cfg.yaml")
+		writeConfigFile(t, filename, "name: first", modTime)
+
+		watcher, c, reloads, reloadErrors := watchConfigFile(t, filename)
+
+		writeConfigFile(t, filename, "name: [first", modTime.Add(time.Minute))
+
+		select {
+		case err := <-reloadErrors:
+			assert.Error(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("reload error was not reported")
+		}
+
+		assert.Equal(t, c, watcher.Config())
+		assert.Len(t, reloads, 0)
+	})
+
+	t.Run("should reload config when a symlinked directory is swapped", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("symlinks require privileges on windows")
+		}
+
+		// Kubernetes mounts ConfigMaps as symlinks to the '..data' symlink, which is swapped on updates.
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "..v1"), 0o755))
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "..v2"), 0o755))
+		writeConfigFile(t, filepath.Join(dir, "..v1", "cfg.yaml"), "name: first", modTime)
+		writeConfigFile(t, filepath.Join(dir, "..v2", "cfg.yaml"), "name: third", modTime)
+		require.NoError(t, os.Symlink("..v1", filepath.Join(dir, "..data")))
+		require.NoError(t, os.Symlink(filepath.Join("..data", "cfg.yaml"), filepath.Join(dir, "cfg.yaml")))
+
+		watcher, _, reloads, _ := watchConfigFile(t, filepath.Join(dir, "cfg.yaml"))
+
+		require.NoError(t, os.Symlink("..v2", filepath.Join(dir, "..data_tmp")))
+		require.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+
+		select {
+		case reload := <-reloads:
+			assert.Equal(t, "third", reload.newCfg.Name)
+			assert.Equal(t, reload.newCfg, watcher.Config())
+		case <-time.After(5 * time.Second):
+			t.Fatal("config was not reloaded")
+		}
+	})
+}
+
+func TestConfigLoader_Watch_CreatedFiles(t *testing.T) {
+	type createdFilesConfig struct {
+		Name  string `yaml:"name"`
+		Level string `yaml:"level"`
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "cfg.yaml"), []byte("name: base"), 0o644))
+
+	reloads := make(chan *createdFilesConfig, 10)
+
+	c := createdFilesConfig{}
+	watcher, err := NewConfigLoader().
+		UseLoadPath(dir).
+		UseFileFormatDetection().
+		UseEnvironment(Production).
+		UseDefaultLoadBehavior().
+		UseWatchInterval(10 * time.Millisecond).
+		OnReload(func(oldCfg, newCfg interface{}) {
+			reloads <- newCfg.(*createdFilesConfig)
+		}).
+		Watch(&c)
+	require.NoError(t, err)
+	defer watcher.Stop()
+
+	assert.Equal(t, createdFilesConfig{Name: "base"}, c)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "cfg.prod.yaml"), []byte("level: prod"), 0o644))
+
+	select {
+	case reloaded := <-reloads:
+		assert.Equal(t, &createdFilesConfig{Name: "base", Level: "prod"}, reloaded)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
+
+func TestWatcher_Reload_Dotenv(t *testing.T) {
+	type dotenvConfig struct {
+		Host string `env:"WATCH_DOTENV_HOST"`
+		Port string `env:"WATCH_DOTENV_PORT"`
+	}
+
+	resetWatchEnv := func() {
+		_ = os.Unsetenv("WATCH_DOTENV_HOST")
+		_ = os.Unsetenv("WATCH_DOTENV_PORT")
+	}
+
+	resetWatchEnv()
+	t.Cleanup(resetWatchEnv)
+
+	require.NoError(t, os.Setenv("WATCH_DOTENV_HOST", "os-host"))
+
+	filename := filepath.Join(t.TempDir(), "cfg.env")
+	require.NoError(t, ioutil.WriteFile(filename, []byte("WATCH_DOTENV_HOST=file-host\nWATCH_DOTENV_PORT=5432"), 0o644))
+
+	c := dotenvConfig{}
+	watcher, err := NewConfigLoader().
+		UseCustomLoadBehavior().
+		UseWatchInterval(time.Hour).
+		LoadFromRequiredFile(filename).
+		Watch(&c)
+	require.NoError(t, err)
+	defer watcher.Stop()
+
+	assert.Equal(t, dotenvConfig{Host: "file-host", Port: "5432"}, c)
+
+	t.Run("should not keep removed variables", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(filename, []byte("# WATCH_DOTENV_HOST=file-host\nWATCH_DOTENV_PORT=5433"), 0o644))
+		require.NoError(t, watcher.Reload())
+
+		assert.Equal(t, &dotenvConfig{Host: "os-host", Port: "5433"}, watcher.Config())
+
+		require.NoError(t, ioutil.WriteFile(filename, []byte(""), 0o644))
+		require.NoError(t, watcher.Reload())
+
+		assert.Equal(t, &dotenvConfig{Host: "os-host"}, watcher.Config())
+		_, ok := os.LookupEnv("WATCH_DOTENV_PORT")
+		assert.False(t, ok)
+	})
+
+	t.Run("should not unset kept variables while reloading", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(filename, []byte("WATCH_DOTENV_PORT=5432"), 0o644))
+
+		stop := make(chan struct{})
+		unset := make(chan bool, 1)
+		go func() {
+			defer close(unset)
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				if _, ok := os.LookupEnv("WATCH_DOTENV_PORT"); !ok {
+					unset <- true
+					return
+				}
+			}
+		}()
+
+		for i := 0; i < 200; i++ {
+			require.NoError(t, watcher.Reload())
+		}
+		close(stop)
+
+		assert.False(t, <-unset)
+	})
+
+	t.Run("should keep variables of previous load when reload fails", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(filename, []byte("WATCH_DOTENV_PORT=5434"), 0o644))
+		require.NoError(t, watcher.Reload())
+
+		require.NoError(t, os.Remove(filename))
+		assert.Error(t, watcher.Reload())
+
+		assert.Equal(t, "5434", os.Getenv("WATCH_DOTENV_PORT"))
+		assert.Equal(t, &dotenvConfig{Host: "os-host", Port: "5434"}, watcher.Config())
+	})
+}
+
+func TestWatcher_Provenance(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "cfg.yaml")
+	require.NoError(t, ioutil.WriteFile(filename, []byte("name: first"), 0o644))
+
+	configLoader := NewConfigLoader().
+		UseCustomLoadBehavior().
+		UseWatchInterval(time.Millisecond).
+		LoadFromFile(filename).
+		LoadFromFile(filepath.Join(dir, "cfg.local.yaml"))
+
+	c := watchTestConfig{}
+	watcher, err := configLoader.Watch(&c)
+	require.NoError(t, err)
+	defer watcher.Stop()
+
+	t.Run("should read state of the config loader while reloading", func(t *testing.T) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 20; i++ {
+				_ = watcher.Reload()
+			}
+		}()
+
+		for i := 0; i < 20; i++ {
+			_ = configLoader.Provenance().String()
+			_ = configLoader.ResolvedPaths()
+			_ = watcher.Provenance().String()
+			_ = watcher.ResolvedPaths()
+		}
+
+		<-done
+	})
+
+	t.Run("should provide state of the current config by the watcher", func(t *testing.T) {
+		localFilename := filepath.Join(dir, "cfg.local.yaml")
+		require.NoError(t, ioutil.WriteFile(localFilename, []byte("name: local"), 0o644))
+		require.NoError(t, watcher.Reload())
+
+		assert.Equal(t, []string{filename}, configLoader.ResolvedPaths())
+		assert.Equal(t, FieldSource{Type: SourceFile, Name: filename, Step: 1}, configLoader.Provenance()["Name"])
+
+		assert.Equal(t, []string{filename, localFilename}, watcher.ResolvedPaths())
+		assert.Equal(t, FieldSource{Type: SourceFile, Name: localFilename, Step: 2}, watcher.Provenance()["Name"])
+	})
+}
+
+func TestWatcher_Stop(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cfg.yaml")
+	require.NoError(t, ioutil.WriteFile(filename, []byte("name: first"), 0o644))
+
+	watchers := make(chan *Watcher, 1)
+	stopped := make(chan struct{})
+
+	c := watchTestConfig{}
+	watcher, err := NewConfigLoader().
+		UseCustomLoadBehavior().
+		UseWatchInterval(10 * time.Millisecond).
+		LoadFromFile(filename).
+		OnReload(func(oldCfg, newCfg interface{}) {
+			(<-watchers).Stop()
+			close(stopped)
+		}).
+		Watch(&c)
+	require.NoError(t, err)
+	watchers <- watcher
+
+	require.NoError(t, ioutil.WriteFile(filename, []byte("name: second"), 0o644))
+	require.NoError(t, os.Chtimes(filename, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+
+	select {
+	case <-stopped:
+		watcher.Stop()
+	case <-time.After(5 * time.Second):
+		t.Fatal("watcher was not stopped by the reload func")
+	}
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	reloaded := 0
+
+	c := watchTestConfig{}
+	watcher, err := NewConfigLoader().
+		UseCustomLoadBehavior().
+		UseWatchInterval(time.Hour).
+		LoadFromBytes([]byte("name: bytes"), YAML).
+		OnReload(func(oldCfg, newCfg interface{}) {
+			reloaded++
+		}).
+		Watch(&c)
+	require.NoError(t, err)
+	defer watcher.Stop()
+
+	require.NoError(t, watcher.Reload())
+
+	assert.Equal(t, 1, reloaded)
+	assert.Equal(t, &watchTestConfig{Name: "bytes"}, watcher.Config())
+	assert.NotSame(t, &c, watcher.Config())
+}
@@ -0,0 +1,2 @@
+// Package yetenvtest provides helpers for forcing the environment and building config directories in unit tests.
+package yetenvtest
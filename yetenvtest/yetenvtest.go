@@ -0,0 +1,122 @@
+package yetenvtest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pvormste/yetenv"
+)
+
+// SetEnvironment sets the variable defined by yetenv.DefaultVariableName to the provided Environment.
+// The prior state of the variable is restored when the test finishes.
+func SetEnvironment(t testing.TB, environment yetenv.Environment) {
+	t.Helper()
+	SetVariable(t, yetenv.DefaultVariableName, string(environment))
+}
+
+// SetVariable sets an OS environment variable and restores its prior state when the test finishes.
+func SetVariable(t testing.TB, name string, value string) {
+	t.Helper()
+	restoreVariableOnCleanup(t, name)
+
+	if err := os.Setenv(name, value); err != nil {
+		t.Fatalf("yetenvtest: could not set variable '%s': %s", name, err)
+	}
+}
+
+// UnsetVariables unsets OS environment variables and restores their prior state when the test finishes.
+func UnsetVariables(t testing.TB, names ...string) {
+	t.Helper()
+
+	for _, name := range names {
+		restoreVariableOnCleanup(t, name)
+
+		if err := os.Unsetenv(name); err != nil {
+			t.Fatalf("yetenvtest: could not unset variable '%s': %s", name, err)
+		}
+	}
+}
+
+// PreserveVariables restores all OS environment variables when the test finishes. This is useful because
+// dotenv files loaded by the yetenv.ConfigLoader are written into the OS environment.
+func PreserveVariables(t testing.TB) {
+	t.Helper()
+
+	environ := os.Environ()
+	t.Cleanup(func() {
+		os.Clearenv()
+		for _, variable := range environ {
+			nameAndValue := strings.SplitN(variable, "=", 2)
+			_ = os.Setenv(nameAndValue[0], nameAndValue[1])
+		}
+	})
+}
+
+// ConfigDir creates a temporary directory with the provided files (file name -> content) and returns its path.
+// The directory is removed when the test finishes.
+func ConfigDir(t testing.TB, files map[string]string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "yetenvtest")
+	if err != nil {
+		t.Fatalf("yetenvtest: could not create config directory: %s", err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	for fileName, content := range files {
+		filePath := filepath.Join(dir, fileName)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("yetenvtest: could not create directory for '%s': %s", fileName, err)
+		}
+
+		if err := ioutil.WriteFile(filePath, []byte(content), 0600); err != nil {
+			t.Fatalf("yetenvtest: could not write config file '%s': %s", fileName, err)
+		}
+	}
+
+	return dir
+}
+
+// EnvironmentConfigDir creates a temporary directory with one config file per environment and returns its path.
+// The file names are the default config file names of a new yetenv.ConfigLoader with the provided extension, so the
+// directory can be used as load path for the default load behavior.
+func EnvironmentConfigDir(t testing.TB, extension yetenv.ConfigFileExtension, contents map[yetenv.Environment]string) string {
+	t.Helper()
+
+	configFiles := yetenv.NewConfigLoader().ConfigFiles
+	files := map[string]string{}
+
+	for environment, content := range contents {
+		configFile, ok := configFiles[environment]
+		if !ok {
+			t.Fatalf("yetenvtest: no config file for environment '%s'", environment)
+		}
+
+		if environment == yetenv.Custom && extension == yetenv.DOTENV {
+			configFile = ""
+		}
+
+		files[configFile+string(extension)] = content
+	}
+
+	return ConfigDir(t, files)
+}
+
+func restoreVariableOnCleanup(t testing.TB, name string) {
+	previousValue, wasSet := os.LookupEnv(name)
+
+	t.Cleanup(func() {
+		if wasSet {
+			_ = os.Setenv(name, previousValue)
+			return
+		}
+
+		_ = os.Unsetenv(name)
+	})
+}
@@ -0,0 +1,78 @@
+package yetenvtest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pvormste/yetenv"
+)
+
+func TestSetEnvironment(t *testing.T) {
+	require.NoError(t, os.Setenv(yetenv.DefaultVariableName, "production"))
+	defer func() { _ = os.Unsetenv(yetenv.DefaultVariableName) }()
+
+	t.Run("should set environment", func(t *testing.T) {
+		SetEnvironment(t, yetenv.Staging)
+		assert.Equal(t, yetenv.Staging, yetenv.GetEnvironment())
+	})
+
+	assert.Equal(t, yetenv.Production, yetenv.GetEnvironment())
+}
+
+func TestUnsetVariables(t *testing.T) {
+	require.NoError(t, os.Setenv("YETENVTEST_VAR", "value"))
+	defer func() { _ = os.Unsetenv("YETENVTEST_VAR") }()
+
+	t.Run("should unset variable", func(t *testing.T) {
+		UnsetVariables(t, "YETENVTEST_VAR")
+
+		_, ok := os.LookupEnv("YETENVTEST_VAR")
+		assert.False(t, ok)
+	})
+
+	assert.Equal(t, "value", os.Getenv("YETENVTEST_VAR"))
+}
+
+func TestPreserveVariables(t *testing.T) {
+	t.Run("should restore variables", func(t *testing.T) {
+		PreserveVariables(t)
+		require.NoError(t, os.Setenv("YETENVTEST_PRESERVED", "value"))
+	})
+
+	_, ok := os.LookupEnv("YETENVTEST_PRESERVED")
+	assert.False(t, ok)
+}
+
+func TestEnvironmentConfigDir(t *testing.T) {
+	type config struct {
+		Value string `env:"YETENVTEST_VALUE"`
+		Base  string `env:"YETENVTEST_BASE"`
+	}
+
+	PreserveVariables(t)
+	UnsetVariables(t, "YETENVTEST_VALUE", "YETENVTEST_BASE")
+
+	dir := EnvironmentConfigDir(t, yetenv.DOTENV, map[yetenv.Environment]string{
+		yetenv.Staging: `YETENVTEST_VALUE="staging"`,
+		yetenv.Custom:  `YETENVTEST_BASE="custom"`,
+	})
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "cfg.staging.env"))
+	require.NoError(t, err)
+	assert.Equal(t, `YETENVTEST_VALUE="staging"`, string(content))
+
+	c := config{}
+	err = yetenv.NewConfigLoader().
+		UseLoadPath(dir).
+		UseEnvironment(yetenv.Staging).
+		UseDefaultLoadBehavior().
+		LoadInto(&c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, config{Value: "staging", Base: "custom"}, c)
+}